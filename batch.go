@@ -0,0 +1,146 @@
+package mailingo
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"runtime"
+	"sync"
+)
+
+// Recipient is a single recipient of a batch render
+type Recipient struct {
+	Name       string                 // Recipient's name, overrides Body.Name of the base email
+	Lang       string                 // BCP 47 language tag to render in (e.g., "en", "zh-CN")
+	Data       map[string]interface{} // Per-recipient template data, merged over the base email's Data
+	TrackingID string                 // Per-recipient tracking ID (optional)
+	Formality  string                 // Overrides Body.Formality of the base email (optional)
+	Gender     string                 // Overrides Body.Gender of the base email (optional)
+}
+
+// BatchOptions configures RenderBatch
+type BatchOptions struct {
+	Workers       int  // Maximum number of concurrent renders (defaults to GOMAXPROCS)
+	SkipHTML      bool // Do not render HTML
+	SkipPlainText bool // Do not render plain text
+}
+
+// BatchResult is the rendered email of a single recipient
+type BatchResult struct {
+	Index     int       // Position of the recipient in the input sequence
+	Recipient Recipient // The recipient the email was rendered for
+	HTML      string    // Rendered HTML, unless skipped
+	Text      string    // Rendered plain text, unless skipped
+	Err       error     // Rendering error of this recipient
+}
+
+// RenderBatch renders the base email for every recipient concurrently, using at most
+// opts.Workers goroutines. Results are delivered on the returned channel as soon as they
+// are ready, so they are not ordered; use BatchResult.Index to correlate them with the input.
+// A failing recipient is reported in its BatchResult and does not stop the batch.
+//
+// The channel is closed once all recipients are rendered or ctx is done. After
+// cancellation, no further recipients are read from the sequence. The caller must
+// either drain the channel or cancel ctx.
+//
+// Example:
+//
+//	results := mailer.RenderBatch(ctx, campaign, slices.Values(recipients), mailingo.BatchOptions{Workers: 8})
+//	for result := range results {
+//	    if result.Err != nil {
+//	        log.Printf("recipient %d: %v", result.Index, result.Err)
+//	        continue
+//	    }
+//	    send(result.Recipient, result.HTML, result.Text)
+//	}
+func (m *Mailer) RenderBatch(ctx context.Context, base Email, recipients iter.Seq[Recipient], opts BatchOptions) <-chan BatchResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	type job struct {
+		index     int
+		recipient Recipient
+	}
+
+	jobs := make(chan job)
+	results := make(chan BatchResult, workers)
+
+	// Feed recipients to the workers until the sequence ends or ctx is done
+	go func() {
+		defer close(jobs)
+		index := 0
+		for recipient := range recipients {
+			select {
+			case jobs <- job{index: index, recipient: recipient}:
+				index++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				result := m.renderRecipient(base, j.recipient, opts)
+				result.Index = j.index
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// renderRecipient renders the base email personalized for the recipient
+func (m *Mailer) renderRecipient(base Email, recipient Recipient, opts BatchOptions) BatchResult {
+	result := BatchResult{Recipient: recipient}
+	email := personalize(base, recipient)
+
+	if !opts.SkipHTML {
+		result.HTML, result.Err = m.GenerateHTML(email, recipient.Lang)
+		if result.Err != nil {
+			return result
+		}
+	}
+	if !opts.SkipPlainText {
+		result.Text, result.Err = m.GeneratePlainText(email, recipient.Lang)
+	}
+	return result
+}
+
+// personalize returns a copy of the base email with the recipient's name, data and tracking ID
+func personalize(base Email, recipient Recipient) Email {
+	email := base
+	if recipient.Name != "" {
+		email.Body.Name = recipient.Name
+	}
+	if recipient.TrackingID != "" {
+		email.TrackingID = recipient.TrackingID
+	}
+	if recipient.Formality != "" {
+		email.Body.Formality = recipient.Formality
+	}
+	if recipient.Gender != "" {
+		email.Body.Gender = recipient.Gender
+	}
+	if len(recipient.Data) > 0 {
+		email.Data = make(map[string]interface{}, len(base.Data)+len(recipient.Data))
+		maps.Copy(email.Data, base.Data)
+		maps.Copy(email.Data, recipient.Data)
+	}
+	return email
+}
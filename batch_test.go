@@ -0,0 +1,119 @@
+package mailingo
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestRenderBatch(t *testing.T) {
+	mailer := New(Product{Name: "Test Product", Link: "https://example.com"}, DefaultTheme)
+	mailer.AddMessages("en", map[string]string{
+		"greeting":       "Hello",
+		"campaign.intro": "Your code is {{.Code}}.",
+	})
+	mailer.AddMessages("zh", map[string]string{
+		"greeting":       "您好",
+		"campaign.intro": "您的优惠码是 {{.Code}}。",
+	})
+
+	base := Email{
+		Body: Body{
+			Intros: []string{"campaign.intro"},
+		},
+		Data: map[string]interface{}{"Code": "DEFAULT"},
+	}
+
+	recipients := []Recipient{
+		{Name: "Alice", Lang: "en", Data: map[string]interface{}{"Code": "ALICE10"}},
+		{Name: "Zhang San", Lang: "zh", Data: map[string]interface{}{"Code": "ZHANG20"}},
+		{Name: "Bob", Lang: "en"},
+	}
+
+	results := make([]BatchResult, len(recipients))
+	for result := range mailer.RenderBatch(context.Background(), base, slices.Values(recipients), BatchOptions{Workers: 2}) {
+		results[result.Index] = result
+	}
+
+	expected := []string{
+		"Hello Alice,\n\nYour code is ALICE10.",
+		"您好 Zhang San,\n\n您的优惠码是 ZHANG20。",
+		"Hello Bob,\n\nYour code is DEFAULT.",
+	}
+
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("Recipient %d failed: %v", i, result.Err)
+			continue
+		}
+		if !strings.HasPrefix(result.Text, expected[i]) {
+			t.Errorf("Recipient %d: expected text to start with %q, got:\n%s", i, expected[i], result.Text)
+		}
+		if !strings.Contains(result.HTML, recipients[i].Name) {
+			t.Errorf("Recipient %d: HTML should contain recipient name", i)
+		}
+	}
+
+	// The base email must not be modified
+	if base.Data["Code"] != "DEFAULT" || base.Body.Name != "" {
+		t.Error("RenderBatch should not modify the base email")
+	}
+}
+
+func TestRenderBatchErrors(t *testing.T) {
+	mailer := New(Product{Name: "Test Product", Link: "https://example.com"}, DefaultTheme)
+
+	base := Email{
+		Body: Body{
+			Actions: []Action{{Button: Button{Text: "Open"}}},
+		},
+	}
+
+	recipients := []Recipient{{Name: "Alice", Lang: "en"}}
+
+	// Per-item errors are reported in the result
+	mailerWithValidation := New(Product{Name: "Test Product"}, DefaultTheme, options.WithValidation())
+	for result := range mailerWithValidation.RenderBatch(context.Background(), base, slices.Values(recipients), BatchOptions{}) {
+		if result.Err == nil {
+			t.Error("Expected validation error in batch result")
+		}
+	}
+
+	// Skipping formats
+	for result := range mailer.RenderBatch(context.Background(), base, slices.Values(recipients), BatchOptions{SkipHTML: true}) {
+		if result.HTML != "" || result.Text == "" {
+			t.Error("Expected only plain text to be rendered")
+		}
+	}
+}
+
+func TestRenderBatchCancel(t *testing.T) {
+	mailer := New(Product{Name: "Test Product", Link: "https://example.com"}, DefaultTheme)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// An endless stream of recipients
+	recipients := func(yield func(Recipient) bool) {
+		for {
+			if !yield(Recipient{Name: "Test User", Lang: "en"}) {
+				return
+			}
+		}
+	}
+
+	count := 0
+	for range mailer.RenderBatch(ctx, Email{}, recipients, BatchOptions{Workers: 4}) {
+		count++
+		if count == 10 {
+			cancel()
+		}
+	}
+
+	if count < 10 {
+		t.Errorf("Expected at least 10 results, got %d", count)
+	}
+}
@@ -0,0 +1,61 @@
+package mailingo
+
+import "container/list"
+
+// lruCache is a map that keeps the most recently used entries up to a maximum size,
+// for caches keyed by untrusted input such as the language of a request.
+// It is not safe for concurrent use.
+type lruCache[K comparable, V any] struct {
+	size    int
+	entries map[K]*list.Element
+	order   list.List // Entries, most recently used first
+}
+
+// lruEntry is an entry of an lruCache
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// newLRUCache returns an empty cache keeping at most size entries
+func newLRUCache[K comparable, V any](size int) *lruCache[K, V] {
+	return &lruCache[K, V]{size: size, entries: make(map[K]*list.Element)}
+}
+
+// get returns the value for key and marks it as most recently used
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// add adds or replaces the value for key, evicting the least recently used entry
+// if the cache is full
+func (c *lruCache[K, V]) add(key K, value V) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+		c.order.Remove(oldest)
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+}
+
+// len returns the number of entries
+func (c *lruCache[K, V]) len() int {
+	return c.order.Len()
+}
+
+// clear removes all entries
+func (c *lruCache[K, V]) clear() {
+	clear(c.entries)
+	c.order.Init()
+}
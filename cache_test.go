@@ -0,0 +1,31 @@
+package mailingo
+
+import "testing"
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache[string, int](2)
+	cache.add("a", 1)
+	cache.add("b", 2)
+
+	// Using "a" makes "b" the least recently used entry
+	if v, ok := cache.get("a"); !ok || v != 1 {
+		t.Fatalf("Expected 1, got %d, %v", v, ok)
+	}
+	cache.add("c", 3)
+	if _, ok := cache.get("b"); ok {
+		t.Error("Least recently used entry should be evicted")
+	}
+	if v, ok := cache.get("a"); !ok || v != 1 {
+		t.Errorf("Recently used entry should be kept, got %d, %v", v, ok)
+	}
+
+	cache.add("c", 4)
+	if v, _ := cache.get("c"); v != 4 || cache.len() != 2 {
+		t.Errorf("Adding an existing key should replace its value, got %d with %d entries", v, cache.len())
+	}
+
+	cache.clear()
+	if _, ok := cache.get("a"); ok || cache.len() != 0 {
+		t.Error("Clear should remove all entries")
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lib-x/mailingo"
+	"github.com/lib-x/mailingo/options"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// mailerConfig is the product, theme and template configuration read from the -config file
+type mailerConfig struct {
+	Product   mailingo.Product    `json:"product"`
+	Theme     json.RawMessage     `json:"theme"` // "default", "flat" or a custom theme object
+	CustomCSS string              `json:"custom_css"`
+	Template  string              `json:"template"`  // Path to a custom HTML template, relative to the config file
+	Fallbacks map[string][]string `json:"fallbacks"` // Fallback chains by language, e.g. {"es-AR": ["es"]}
+}
+
+// themes are the built-in themes selectable by name
+var themes = map[string]mailingo.Theme{
+	"default": mailingo.DefaultTheme,
+	"flat":    mailingo.FlatTheme,
+}
+
+// decodeFile decodes a JSON or YAML file into v. YAML is chosen by the
+// .yaml or .yml extension and converted to JSON, so both formats share
+// the same field names. Unknown fields are rejected to catch typos.
+func decodeFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if data, err = json.Marshal(raw); err != nil {
+			return fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// theme resolves the configured theme, defaulting to mailingo.DefaultTheme
+func (c mailerConfig) theme() (mailingo.Theme, error) {
+	if len(c.Theme) == 0 || string(c.Theme) == "null" {
+		return mailingo.DefaultTheme, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(c.Theme, &name); err == nil {
+		theme, ok := themes[strings.ToLower(name)]
+		if !ok {
+			return mailingo.Theme{}, fmt.Errorf("unknown theme %q", name)
+		}
+		return theme, nil
+	}
+
+	var theme mailingo.Theme
+	if err := json.Unmarshal(c.Theme, &theme); err != nil {
+		return mailingo.Theme{}, fmt.Errorf("invalid theme: %w", err)
+	}
+	return theme, nil
+}
+
+// loadConfig reads the config file, which is optional
+func loadConfig(configPath string) (mailerConfig, error) {
+	var config mailerConfig
+	if configPath != "" {
+		if err := decodeFile(configPath, &config); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+// templatePath returns the path of the custom template, relative to the config
+// file, or an empty string if none is configured
+func (c mailerConfig) templatePath(configPath string) string {
+	if c.Template == "" || filepath.IsAbs(c.Template) {
+		return c.Template
+	}
+	return filepath.Join(filepath.Dir(configPath), c.Template)
+}
+
+// options returns the mailer options of the config, except the template.
+// Invalid language tags in the fallback chains are rejected, since they
+// make mailingo.New panic.
+func (c mailerConfig) options() ([]options.Option, error) {
+	var opts []options.Option
+	if c.CustomCSS != "" {
+		opts = append(opts, options.WithCustomCSS(c.CustomCSS))
+	}
+	for lang, fallbacks := range c.Fallbacks {
+		if _, err := language.Parse(lang); err != nil {
+			return nil, fmt.Errorf("invalid fallback language %q: %w", lang, err)
+		}
+		for _, fallback := range fallbacks {
+			if _, err := language.Parse(fallback); err != nil {
+				return nil, fmt.Errorf("invalid fallback %q of %q: %w", fallback, lang, err)
+			}
+		}
+		opts = append(opts, options.WithFallback(lang, fallbacks...))
+	}
+	return opts, nil
+}
+
+// newMailer creates a mailer from the config file and locale directory.
+// Both are optional.
+func newMailer(configPath, localeDir string) (*mailingo.Mailer, error) {
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	theme, err := config.theme()
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := config.options()
+	if err != nil {
+		return nil, err
+	}
+
+	mailer := mailingo.New(config.Product, theme, opts...)
+	if localeDir != "" || config.Template != "" {
+		if err := mailer.Reload(localeDir, config.templatePath(configPath)); err != nil {
+			return nil, err
+		}
+	}
+	return mailer, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+
+	"github.com/lib-x/mailingo"
+)
+
+// emlMessage is the content of an .eml file
+type emlMessage struct {
+	From        string
+	To          string
+	Subject     string
+	Lang        string
+	HTML        string
+	Text        string
+	Attachments []mailingo.SMTPAttachment
+}
+
+// writeEML writes msg as a MIME message. The HTML and plain text versions are sent as
+// multipart/alternative, wrapped in multipart/mixed when there are attachments.
+func writeEML(w io.Writer, msg emlMessage) error {
+	bw := bufio.NewWriter(w)
+
+	writeHeader := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(bw, "%s: %s\r\n", key, value)
+		}
+	}
+	writeHeader("From", msg.From)
+	writeHeader("To", msg.To)
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("Content-Language", msg.Lang)
+	writeHeader("MIME-Version", "1.0")
+
+	var alternative, mixed *multipart.Writer
+	if len(msg.Attachments) > 0 {
+		mixed = multipart.NewWriter(bw)
+		writeHeader("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mixed.Boundary()}))
+		bw.WriteString("\r\n")
+
+		// The alternative boundary is needed for the part header before the part exists
+		boundary := multipart.NewWriter(nil).Boundary()
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": boundary})},
+		})
+		if err != nil {
+			return err
+		}
+		alternative = multipart.NewWriter(part)
+		if err := alternative.SetBoundary(boundary); err != nil {
+			return err
+		}
+	} else {
+		alternative = multipart.NewWriter(bw)
+		writeHeader("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": alternative.Boundary()}))
+		bw.WriteString("\r\n")
+	}
+
+	if err := writeTextPart(alternative, "text/plain", msg.Text); err != nil {
+		return err
+	}
+	if err := writeTextPart(alternative, "text/html", msg.HTML); err != nil {
+		return err
+	}
+	if err := alternative.Close(); err != nil {
+		return err
+	}
+
+	if mixed != nil {
+		for _, attachment := range msg.Attachments {
+			if err := writeAttachmentPart(mixed, attachment); err != nil {
+				return err
+			}
+		}
+		if err := mixed.Close(); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeTextPart writes a quoted-printable encoded UTF-8 text part
+func writeTextPart(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := io.WriteString(qp, content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeAttachmentPart writes a base64 encoded attachment with 76 character lines
+func writeAttachmentPart(w *multipart.Writer, attachment mailingo.SMTPAttachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(part, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = io.WriteString(part, encoded+"\r\n")
+	return err
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lib-x/mailingo"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// runExtract implements the extract command
+func runExtract(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mailingo extract [flags] email.yaml...")
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "Product, theme and template configuration file (.json, .yaml or .yml)")
+	sourcePath := flags.String("source", "", "Locale .json file whose messages prefill the skeleton (e.g., locales/en.json)")
+	output := flags.String("o", "", "Output file (defaults to stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("at least one email definition file is required")
+	}
+
+	mailer, err := newMailer(*configPath, "")
+	if err != nil {
+		return err
+	}
+
+	var source map[string]*i18n.Message
+	if *sourcePath != "" {
+		if source, err = loadMessages(*sourcePath); err != nil {
+			return err
+		}
+	}
+
+	skeleton := make(map[string]interface{})
+	for _, path := range flags.Args() {
+		var email mailingo.Email
+		if err := decodeFile(path, &email); err != nil {
+			return err
+		}
+		for _, id := range mailer.MessageIDs(email) {
+			skeleton[id] = skeletonMessage(source[id])
+		}
+	}
+
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(skeleton)
+	})
+}
+
+// loadMessages reads the messages of a locale .json file by message ID
+func loadMessages(path string) (map[string]*i18n.Message, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	messageFile, err := i18n.ParseMessageFileBytes(buf, path, map[string]i18n.UnmarshalFunc{"json": json.Unmarshal})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	messages := make(map[string]*i18n.Message, len(messageFile.Messages))
+	for _, message := range messageFile.Messages {
+		messages[message.ID] = message
+	}
+	return messages, nil
+}
+
+// skeletonMessage returns the skeleton entry of a message: its text, its plural
+// forms if it has any, or an empty string for messages missing from the source
+func skeletonMessage(message *i18n.Message) interface{} {
+	if message == nil {
+		return ""
+	}
+
+	forms := map[string]string{
+		"zero": message.Zero,
+		"one":  message.One,
+		"two":  message.Two,
+		"few":  message.Few,
+		"many": message.Many,
+	}
+	plural := make(map[string]string)
+	for category, text := range forms {
+		if text != "" {
+			plural[category] = text
+		}
+	}
+	if len(plural) == 0 {
+		return message.Other
+	}
+	plural["other"] = message.Other
+	return plural
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lib-x/mailingo"
+)
+
+// runLintLocales implements the lint-locales command
+func runLintLocales(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("lint-locales", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mailingo lint-locales [flags] locales")
+		flags.PrintDefaults()
+	}
+	reference := flags.String("ref", "en", "Language of the reference message file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("exactly one locale directory is required")
+	}
+
+	report, err := mailingo.LintLocales(os.DirFS(flags.Arg(0)), *reference)
+	if err != nil {
+		return err
+	}
+	for _, issue := range report.Issues {
+		fmt.Fprintln(stdout, issue)
+	}
+	if !report.OK() {
+		return fmt.Errorf("%d issues found in %d files", len(report.Issues), len(report.Files))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Command mailingo renders mailingo emails from definition files, so emails can be
+// previewed and iterated on without writing Go code.
+//
+// Usage:
+//
+//	mailingo render -email welcome.yaml -config mailer.yaml -locales locales -lang zh -format html -o welcome.html
+//	mailingo preview -config mailer.yaml -locales locales welcome.yaml reset.yaml
+//	mailingo lint-locales -ref en locales
+//	mailingo extract -config mailer.yaml -source locales/en.json -o de.json welcome.yaml reset.yaml
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a mailingo subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+// commands lists all subcommands in the order shown by the usage message
+var commands = []command{
+	{"render", "Render an email definition to HTML, plain text or .eml", runRender},
+	{"preview", "Serve email definitions on a local preview server", runPreview},
+	{"lint-locales", "Check locale files for missing, extra and inconsistent messages", runLintLocales},
+	{"extract", "Write a skeleton locale file with the message IDs of email definitions", runExtract},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by args[0] and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	if args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		usage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			err := cmd.run(args[1:], stdout, stderr)
+			if errors.Is(err, flag.ErrHelp) {
+				// The flag package already printed the usage
+				return 0
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "mailingo %s: %v\n", cmd.name, err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	fmt.Fprintf(stderr, "mailingo: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+// writeOutput calls write with the file at path, or with stdout if path is empty.
+// The file is only created once the command is ready to write, so invalid flags
+// do not leave an empty or truncated file behind.
+func writeOutput(path string, stdout io.Writer, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// usage prints the list of subcommands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: mailingo <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "mailingo <command> -h" for the flags of a command.`)
+}
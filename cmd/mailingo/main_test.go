@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"render",
+		"-email", "testdata/welcome.yaml",
+		"-config", "testdata/mailer.yaml",
+		"-locales", "testdata/locales",
+		"-lang", "zh",
+	}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("render failed with code %d: %s", code, stderr.String())
+	}
+
+	html := stdout.String()
+	for _, s := range []string{"您好 Jane Smith", "欢迎来到 Acme！", "https://acme.com/confirm", "#2F3133"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("HTML should contain %q", s)
+		}
+	}
+}
+
+func TestRenderTrace(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "mailer.json")
+	os.WriteFile(config, []byte(`{"fallbacks": {"zh-TW": ["zh"]}}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"render",
+		"-email", "testdata/welcome.yaml",
+		"-config", config,
+		"-locales", "testdata/locales",
+		"-lang", "zh-TW",
+		"-trace",
+	}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("render failed with code %d: %s", code, stderr.String())
+	}
+
+	if want := `"email.welcome.title" zh-TW: zh (chain zh-TW, zh, en)`; !strings.Contains(stderr.String(), want) {
+		t.Errorf("Trace should contain %q, got:\n%s", want, stderr.String())
+	}
+}
+
+func TestRenderInvalidFallback(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "mailer.json")
+	os.WriteFile(config, []byte(`{"fallbacks": {"zh-TW": ["not a tag"]}}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"render",
+		"-email", "testdata/welcome.yaml",
+		"-config", config,
+	}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for an invalid fallback, got %d", code)
+	}
+	if want := `invalid fallback "not a tag" of "zh-TW"`; !strings.Contains(stderr.String(), want) {
+		t.Errorf("Error should contain %q, got %q", want, stderr.String())
+	}
+}
+
+func TestRenderEML(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"render",
+		"-email", "testdata/welcome.yaml",
+		"-config", "testdata/mailer.yaml",
+		"-locales", "testdata/locales",
+		"-format", "eml",
+		"-from", "Acme <hello@acme.com>",
+		"-to", "jane@example.com",
+	}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("render failed with code %d: %s", code, stderr.String())
+	}
+
+	msg, err := mail.ReadMessage(&stdout)
+	if err != nil {
+		t.Fatalf("Failed to parse eml: %v", err)
+	}
+
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Welcome to Acme!" {
+		t.Errorf("Expected translated title as subject, got %q", subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Expected multipart/mixed, got %q (%v)", mediaType, err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		types = append(types, part.Header.Get("Content-Type"))
+		if part.FileName() == "terms.txt" {
+			content, _ := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+			if string(content) != "Terms of service" {
+				t.Errorf("Unexpected attachment content %q", content)
+			}
+		}
+	}
+
+	if len(types) != 2 || !strings.HasPrefix(types[0], "multipart/alternative") || types[1] != "text/plain" {
+		t.Errorf("Unexpected parts %v", types)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"bogus"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+
+	if code := run([]string{"render"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 without -email, got %d", code)
+	}
+
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 without a command, got %d", code)
+	}
+
+	for _, help := range []string{"-h", "help"} {
+		stdout.Reset()
+		if code := run([]string{help}, &stdout, &stderr); code != 0 {
+			t.Errorf("Expected exit code 0 for %s, got %d", help, code)
+		}
+		if !strings.Contains(stdout.String(), "Commands:") {
+			t.Errorf("%s should print the usage, got %q", help, stdout.String())
+		}
+	}
+
+	stderr.Reset()
+	if code := run([]string{"render", "-h"}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected exit code 0 for -h, got %d", code)
+	}
+
+	if strings.Contains(stderr.String(), "help requested") {
+		t.Errorf("-h should only print the usage, got %q", stderr.String())
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "welcome.html")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"render",
+		"-email", "testdata/welcome.yaml",
+		"-format", "pdf",
+		"-o", output,
+	}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for an unknown format, got %d", code)
+	}
+
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("Unknown format should not create the output file, got %v", err)
+	}
+}
+
+func TestLintLocales(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"lint-locales", "testdata/locales"}, &stdout, &stderr); code != 0 {
+		t.Errorf("Consistent locales should pass, got code %d: %s", code, stderr.String())
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hello {{.Name}}", "signature": "Best regards"}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"greeting": "Hallo"}`), 0o644)
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"lint-locales", "-ref", "en", dir}, &stdout, &stderr); code != 1 {
+		t.Errorf("Inconsistent locales should fail with code 1, got %d", code)
+	}
+	want := "de.json: missing \"signature\": not translated from en.json\n" +
+		"de.json: placeholders \"greeting\": uses no placeholders, en.json uses {{.Name}}\n"
+	if stdout.String() != want {
+		t.Errorf("Expected issues:\n%s\ngot:\n%s", want, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "2 issues found") {
+		t.Errorf("Expected issue count on stderr, got %q", stderr.String())
+	}
+}
+
+func TestExtract(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"extract", "-source", "testdata/locales/zh.json", "testdata/welcome.yaml"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("extract failed with code %d: %s", code, stderr.String())
+	}
+
+	var skeleton map[string]string
+	if err := json.Unmarshal(stdout.Bytes(), &skeleton); err != nil {
+		t.Fatalf("Skeleton should be a locale file: %v\n%s", err, stdout.String())
+	}
+	want := map[string]string{
+		"greeting":            "您好",
+		"signature":           "此致敬礼",
+		"product.copyright":   "© 2025 Acme 公司。保留所有权利。",
+		"email.welcome.title": "欢迎来到 Acme！",
+	}
+	for id, text := range want {
+		if skeleton[id] != text {
+			t.Errorf("Expected %q for %q, got %q", text, id, skeleton[id])
+		}
+	}
+}
+
+func TestPreviewThemes(t *testing.T) {
+	themes, err := previewThemes(mailerConfig{Theme: []byte(`"flat"`)})
+	if err != nil {
+		t.Fatalf("previewThemes failed: %v", err)
+	}
+	if len(themes) != 2 || themes[0].Name != "flat" || themes[1].Name != "default" {
+		t.Errorf("Configured theme should come first, got %v", themes)
+	}
+
+	themes, err = previewThemes(mailerConfig{Theme: []byte(`{"button_color": "#FF0000"}`)})
+	if err != nil {
+		t.Fatalf("previewThemes failed: %v", err)
+	}
+	if len(themes) != 3 || themes[0].Name != "custom" || themes[0].Theme.ButtonColor != "#FF0000" {
+		t.Errorf("Custom theme should be offered first, got %v", themes)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/lib-x/mailingo"
+	"github.com/lib-x/mailingo/preview"
+)
+
+// runPreview implements the preview command
+func runPreview(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mailingo preview [flags] email.yaml...")
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "Product, theme and template configuration file (.json, .yaml or .yml)")
+	localeDir := flags.String("locales", "", "Directory with locale .json files, reloaded on change")
+	addr := flags.String("addr", "localhost:8080", "Address to listen on")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("at least one email definition file is required")
+	}
+
+	samples := make([]preview.Sample, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		var email mailingo.Email
+		if err := decodeFile(path, &email); err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		samples = append(samples, preview.Sample{Name: name, Email: email})
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	themes, err := previewThemes(config)
+	if err != nil {
+		return err
+	}
+	opts, err := config.options()
+	if err != nil {
+		return err
+	}
+
+	server, err := preview.New(preview.Config{
+		Product:      config.Product,
+		Themes:       themes,
+		Options:      opts,
+		LocaleDir:    *localeDir,
+		TemplateFile: config.templatePath(*configPath),
+		Samples:      samples,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go server.Watch(ctx, func(err error) {
+		fmt.Fprintf(stderr, "mailingo preview: reload failed: %v\n", err)
+	})
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	fmt.Fprintf(stdout, "Serving preview on http://%s (press Ctrl+C to stop)\n", listener.Addr())
+	if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// previewThemes returns the selectable themes with the configured theme first.
+// A custom theme object is offered as "custom" next to the built-in themes.
+func previewThemes(config mailerConfig) ([]preview.Theme, error) {
+	configured, err := config.theme()
+	if err != nil {
+		return nil, err
+	}
+
+	var themes []preview.Theme
+	for _, theme := range preview.DefaultThemes {
+		if theme.Theme == configured {
+			themes = append([]preview.Theme{theme}, themes...)
+		} else {
+			themes = append(themes, theme)
+		}
+	}
+	if len(themes) > 0 && themes[0].Theme != configured {
+		themes = append([]preview.Theme{{Name: "custom", Theme: configured}}, themes...)
+	}
+	return themes, nil
+}
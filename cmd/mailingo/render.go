@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lib-x/mailingo"
+)
+
+// runRender implements the render command
+func runRender(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	emailPath := flags.String("email", "", "Email definition file (.json, .yaml or .yml, required)")
+	configPath := flags.String("config", "", "Product, theme and template configuration file (.json, .yaml or .yml)")
+	localeDir := flags.String("locales", "", "Directory with locale .json files (e.g., locales/en.json)")
+	lang := flags.String("lang", "en", "Language to render (BCP 47 tag)")
+	format := flags.String("format", "html", "Output format: html, text or eml")
+	output := flags.String("o", "", "Output file (defaults to stdout)")
+	from := flags.String("from", "", "From header for eml output")
+	to := flags.String("to", "", "To header for eml output")
+	subject := flags.String("subject", "", "Subject for eml output (supports i18n key, defaults to Body.Title)")
+	trace := flags.Bool("trace", false, "Print the catalog each message ID was resolved from to stderr")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *emailPath == "" {
+		return errors.New("-email is required")
+	}
+
+	var email mailingo.Email
+	if err := decodeFile(*emailPath, &email); err != nil {
+		return err
+	}
+
+	mailer, err := newMailer(*configPath, *localeDir)
+	if err != nil {
+		return err
+	}
+	if *trace {
+		for _, t := range mailer.TraceEmail(email, *lang) {
+			fmt.Fprintln(stderr, t)
+		}
+	}
+
+	var render func(w io.Writer) error
+	switch strings.ToLower(*format) {
+	case "html":
+		render = func(w io.Writer) error {
+			return mailer.RenderHTMLTo(w, email, *lang)
+		}
+	case "text":
+		render = func(w io.Writer) error {
+			return mailer.RenderTextTo(w, email, *lang)
+		}
+	case "eml":
+		render = func(w io.Writer) error {
+			html, err := mailer.GenerateHTML(email, *lang)
+			if err != nil {
+				return err
+			}
+			text, err := mailer.GeneratePlainText(email, *lang)
+			if err != nil {
+				return err
+			}
+
+			subjectKey := *subject
+			if subjectKey == "" {
+				subjectKey = email.Body.Title
+			}
+			return writeEML(w, emlMessage{
+				From:        *from,
+				To:          *to,
+				Subject:     mailer.Translate(subjectKey, *lang, email.Data),
+				Lang:        *lang,
+				HTML:        html,
+				Text:        text,
+				Attachments: email.SMTPAttachments,
+			})
+		}
+	default:
+		return fmt.Errorf("unknown format %q, use html, text or eml", *format)
+	}
+
+	return writeOutput(*output, stdout, render)
+}
@@ -0,0 +1,83 @@
+package mailingo
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Message IDs with built-in translations for en, es, fr, de, pt, zh, ja, ko, ar and ru.
+// Messages with the same ID in loaded message files take precedence.
+const (
+	MessageGreeting    = "greeting"             // Default of Body.Greeting
+	MessageSignature   = "signature"            // Default of Body.Signature
+	MessageCopyright   = "product.copyright"    // Default of Product.Copyright, with {{.Year}} and {{.ProductName}}
+	MessageAttachments = "mailingo.attachments" // Title of the attachments section
+)
+
+//go:embed locales/*.json
+var defaultLocalesFS embed.FS
+
+// defaultBundle returns the bundle of built-in default translations
+var defaultBundle = sync.OnceValue(func() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+
+	files, err := fs.Glob(defaultLocalesFS, "locales/*.json")
+	if err != nil {
+		panic(err)
+	}
+	for _, path := range files {
+		buf, err := defaultLocalesFS.ReadFile(path)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := bundle.ParseMessageFileBytes(buf, path); err != nil {
+			panic(err)
+		}
+	}
+	return bundle
+})
+
+// defaultLanguages returns the languages of the built-in default translations
+var defaultLanguages = sync.OnceValue(func() []language.Tag {
+	return defaultBundle().LanguageTags()
+})
+
+// defaultLocalizers caches the localizers of the default bundle by language tag
+var defaultLocalizers sync.Map
+
+// defaultMessage translates a message ID with the built-in default translations and
+// returns the language of the built-in catalog used. The tag is chosen by builtinLanguage.
+func defaultMessage(tag language.Tag, messageID string, data map[string]interface{}) (string, language.Tag, bool) {
+	localizer, ok := defaultLocalizers.Load(tag)
+	if !ok {
+		localizer, _ = defaultLocalizers.LoadOrStore(tag, i18n.NewLocalizer(defaultBundle(), tag.String()))
+	}
+
+	result, resultTag, err := localizer.(*i18n.Localizer).LocalizeWithTag(&i18n.LocalizeConfig{
+		MessageID:    messageID,
+		TemplateData: data,
+	})
+	if err != nil {
+		return "", language.Und, false
+	}
+	return result, resultTag, true
+}
+
+// defaultMessageData returns the template data for built-in translations:
+// the email's data plus Year and ProductName, unless the data sets them
+func (m *Mailer) defaultMessageData(data map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{
+		"Year":        time.Now().Year(),
+		"ProductName": m.product.Name,
+	}
+	maps.Copy(merged, data)
+	return merged
+}
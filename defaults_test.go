@@ -0,0 +1,140 @@
+package mailingo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestBuiltinLabels(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+	if err := mailer.LoadMessageFile("testdata/zh.json"); err != nil {
+		t.Fatalf("Failed to load message file: %v", err)
+	}
+
+	email := Email{Body: Body{
+		Name:        "Jane",
+		Attachments: []Attachment{{Name: "invoice.pdf", URL: "https://acme.com/invoice.pdf"}},
+	}}
+
+	html, err := mailer.GenerateHTML(email, "zh-CN")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if !strings.Contains(html, "📎 附件") {
+		t.Error("HTML attachments title should be translated")
+	}
+
+	text, err := mailer.GeneratePlainText(email, "zh")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+	if !strings.Contains(text, "附件:\n") {
+		t.Errorf("Plain text attachments title should be translated, got %q", text)
+	}
+
+	// Languages without loaded messages use their built-in translations
+	text, _ = mailer.GeneratePlainText(email, "fr")
+	if !strings.Contains(text, "Pièces jointes:\n") {
+		t.Errorf("Labels of languages without loaded messages should use their built-in translation, got %q", text)
+	}
+
+	// Loaded languages without built-in translations use the default language
+	if err := mailer.AddMessages("it", map[string]string{"greeting": "Ciao"}); err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+	text, _ = mailer.GeneratePlainText(email, "it")
+	if !strings.HasPrefix(text, "Ciao Jane") || !strings.Contains(text, "Attachments:\n") {
+		t.Errorf("Labels should follow the language the messages resolved to, got %q", text)
+	}
+}
+
+func TestBuiltinLabelsOverride(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+	if err := mailer.AddMessages("en", map[string]string{MessageAttachments: "Files"}); err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	text, err := mailer.GeneratePlainText(Email{Body: Body{
+		Attachments: []Attachment{{Name: "invoice.pdf", URL: "https://acme.com/invoice.pdf"}},
+	}}, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+	if !strings.Contains(text, "Files:\n") {
+		t.Errorf("Loaded messages should override built-in labels, got %q", text)
+	}
+}
+
+func TestBuiltinBeatsOtherLanguages(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+	err := mailer.LoadMessageFileFS(fstest.MapFS{
+		"en.json": {Data: []byte(`{"greeting": "Hi there", "email.title": "Welcome"}`)},
+	}, "en.json")
+	if err != nil {
+		t.Fatalf("LoadMessageFileFS failed: %v", err)
+	}
+
+	text, err := mailer.GeneratePlainText(Email{Body: Body{Name: "Jane", Title: "email.title"}}, "fr")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+	if !strings.HasPrefix(text, "Bonjour Jane") || !strings.Contains(text, "Cordialement") {
+		t.Errorf("Built-in French should beat the English file for fr, got %q", text)
+	}
+	if !strings.Contains(text, "Welcome") {
+		t.Errorf("Messages without built-in translation should still come from the English file, got %q", text)
+	}
+
+	if got := mailer.Translate("greeting", "en", nil); got != "Hi there" {
+		t.Errorf("Loaded messages should take precedence within their language, got %q", got)
+	}
+	if trace := mailer.TraceMessage("greeting", "fr", nil); !trace.Builtin || trace.Catalog != "fr" {
+		t.Errorf("Unexpected trace %v", trace)
+	}
+}
+
+func TestBuiltinCatalogs(t *testing.T) {
+	for _, lang := range []string{"en", "es", "fr", "de", "pt", "zh", "ja", "ko", "ar", "ru"} {
+		mailer := New(Product{}, DefaultTheme)
+		for _, id := range []string{MessageGreeting, MessageSignature, MessageCopyright, MessageAttachments} {
+			english := New(Product{}, DefaultTheme).Translate(id, "en", nil)
+			text := mailer.Translate(id, lang, nil)
+			if text == id || (lang != "en" && text == english) {
+				t.Errorf("Missing built-in %s translation of %s, got %q", lang, id, text)
+			}
+		}
+	}
+}
+
+func TestBuiltinDefaults(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+
+	text, err := mailer.GeneratePlainText(Email{Body: Body{Name: "Jane"}}, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+	year := time.Now().Year()
+	want := fmt.Sprintf("Hello Jane,\n\nBest regards,\nAcme\n\n© %d Acme. All rights reserved.", year)
+	if text != want {
+		t.Errorf("Without message files, expected %q, got %q", want, text)
+	}
+
+	// Loaded messages take precedence, missing ones use the built-in translation of the language
+	if err := mailer.AddMessages("de", map[string]string{"signature": "Ihr Acme-Team"}); err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+	text, _ = mailer.GeneratePlainText(Email{Body: Body{Name: "Jane"}}, "de-AT")
+	want = fmt.Sprintf("Hallo Jane,\n\nIhr Acme-Team,\nAcme\n\n© %d Acme. Alle Rechte vorbehalten.", year)
+	if text != want {
+		t.Errorf("Expected %q, got %q", want, text)
+	}
+
+	// Email data can set the copyright year
+	copyright := mailer.Translate(MessageCopyright, "en", map[string]interface{}{"Year": 2020})
+	if copyright != "© 2020 Acme. All rights reserved." {
+		t.Errorf("Data should override Year, got %q", copyright)
+	}
+}
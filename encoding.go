@@ -0,0 +1,49 @@
+package mailingo
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// smtpAttachmentYAML is the YAML representation of SMTPAttachment.
+// Content is base64-encoded explicitly, as encoding/json does for []byte,
+// so both formats share the same schema.
+type smtpAttachmentYAML struct {
+	Filename    string `yaml:"filename,omitempty"`
+	Content     string `yaml:"content,omitempty"`
+	ContentType string `yaml:"content_type,omitempty"`
+}
+
+// MarshalYAML encodes the attachment with base64-encoded content.
+// It implements the yaml.Marshaler interface of gopkg.in/yaml.v2 and v3.
+func (a SMTPAttachment) MarshalYAML() (interface{}, error) {
+	return smtpAttachmentYAML{
+		Filename:    a.Filename,
+		Content:     base64.StdEncoding.EncodeToString(a.Content),
+		ContentType: a.ContentType,
+	}, nil
+}
+
+// UnmarshalYAML decodes an attachment with base64-encoded content.
+// It implements the yaml.Unmarshaler interface of gopkg.in/yaml.v2, which v3 also supports.
+func (a *SMTPAttachment) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw smtpAttachmentYAML
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	content, err := base64.StdEncoding.DecodeString(raw.Content)
+	if err != nil {
+		return fmt.Errorf("invalid base64 content of attachment %q: %w", raw.Filename, err)
+	}
+
+	*a = SMTPAttachment{
+		Filename:    raw.Filename,
+		Content:     content,
+		ContentType: raw.ContentType,
+	}
+	if len(content) == 0 {
+		a.Content = nil
+	}
+	return nil
+}
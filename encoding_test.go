@@ -0,0 +1,195 @@
+package mailingo
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serializationEmail uses every field, so round trips cover the whole schema
+func serializationEmail() Email {
+	return Email{
+		Body: Body{
+			Name:       "Jane Smith",
+			Greeting:   "greeting.formal",
+			Signature:  "signature",
+			Title:      "email.order.title",
+			Intros:     []string{"email.order.intro"},
+			Outros:     []string{"email.order.outro"},
+			Dictionary: []Entry{{Key: "email.order.number", Value: "#12345"}},
+			Table: Table{
+				Header:    []string{"Item", "Price"},
+				Rows:      []TableRow{{Key: "1", Cells: []string{"Widget", "$10"}}},
+				KeyHeader: "#",
+				Data:      [][]Entry{{{Key: "Item", Value: "Widget"}}},
+				Columns: Columns{
+					CustomWidth:     map[string]string{"Item": "70%"},
+					CustomAlignment: map[string]string{"Price": "right"},
+				},
+			},
+			Actions: []Action{{
+				Instructions:   "email.order.instructions",
+				Button:         Button{Text: "email.order.button", Link: "https://example.com/order", Color: "#22BC66"},
+				InvertedButton: true,
+			}},
+			Attachments: []Attachment{{Name: "invoice.pdf", URL: "https://example.com/invoice.pdf", Size: "1 MB", Type: "PDF"}},
+		},
+		SMTPAttachments: []SMTPAttachment{{
+			Filename:    "terms.bin",
+			Content:     []byte{0x00, 0xFF, 'T', 'e', 'r', 'm', 's'},
+			ContentType: "application/octet-stream",
+		}},
+		TrackingID:      "msg-1",
+		DisableTracking: true,
+		Data:            map[string]interface{}{"OrderID": "12345"},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	email := serializationEmail()
+
+	data, err := json.Marshal(email)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded Email
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(email, decoded) {
+		t.Errorf("JSON round trip changed the email:\n%+v\n%+v", email, decoded)
+	}
+
+	for _, key := range []string{`"smtp_attachments"`, `"content":"AP9UZXJtcw=="`, `"key_header"`, `"custom_width"`, `"inverted_button"`, `"disable_tracking"`} {
+		if !bytes.Contains(data, []byte(key)) {
+			t.Errorf("JSON should contain %s", key)
+		}
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	email := serializationEmail()
+
+	data, err := yaml.Marshal(email)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded Email
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(email, decoded) {
+		t.Errorf("YAML round trip changed the email:\n%+v\n%+v", email, decoded)
+	}
+
+	if !strings.Contains(string(data), "content: AP9UZXJtcw==") {
+		t.Errorf("YAML should contain base64 content, got:\n%s", data)
+	}
+
+	var invalid Email
+	err = yaml.Unmarshal([]byte("smtp_attachments:\n  - filename: a.txt\n    content: not base64!\n"), &invalid)
+	if err == nil {
+		t.Error("Invalid base64 content should fail")
+	}
+}
+
+func TestSerializationOmitsEmptyFields(t *testing.T) {
+	email := Email{Body: Body{Name: "Jane"}}
+
+	data, err := json.Marshal(email)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"body":{"name":"Jane"}}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	data, err = yaml.Marshal(email)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != "body:\n    name: Jane\n" {
+		t.Errorf("Unexpected YAML %q", data)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+
+	published, err := os.ReadFile("email.schema.json")
+	if err != nil {
+		t.Fatalf("Failed to read email.schema.json: %v", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(published), schema) {
+		t.Error("email.schema.json is outdated, run go generate")
+	}
+
+	var doc struct {
+		Ref  string                     `json:"$ref"`
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	if doc.Ref != "#/$defs/Email" {
+		t.Errorf("Schema should reference Email, got %q", doc.Ref)
+	}
+
+	// Every key of a fully populated email must be declared in the schema
+	data, _ := json.Marshal(serializationEmail())
+	var value interface{}
+	json.Unmarshal(data, &value)
+	checkSchemaKeys(t, doc.Defs, "Email", value, "")
+}
+
+// checkSchemaKeys reports keys of value that are not properties of the definition
+func checkSchemaKeys(t *testing.T, defs map[string]json.RawMessage, def string, value interface{}, path string) {
+	t.Helper()
+
+	var schema struct {
+		Properties map[string]struct {
+			Ref   string `json:"$ref"`
+			Items *struct {
+				Ref   string `json:"$ref"`
+				Items *struct {
+					Ref string `json:"$ref"`
+				} `json:"items"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	json.Unmarshal(defs[def], &schema)
+
+	for key, child := range value.(map[string]interface{}) {
+		property, ok := schema.Properties[key]
+		if !ok {
+			t.Errorf("Schema of %s does not declare %s%s", def, path, key)
+			continue
+		}
+
+		name := func(ref string) string { return strings.TrimPrefix(ref, "#/$defs/") }
+		switch {
+		case property.Ref != "":
+			checkSchemaKeys(t, defs, name(property.Ref), child, path+key+".")
+		case property.Items != nil && property.Items.Ref != "":
+			for _, item := range child.([]interface{}) {
+				checkSchemaKeys(t, defs, name(property.Items.Ref), item, path+key+"[].")
+			}
+		case property.Items != nil && property.Items.Items != nil && property.Items.Items.Ref != "":
+			for _, row := range child.([]interface{}) {
+				for _, item := range row.([]interface{}) {
+					checkSchemaKeys(t, defs, name(property.Items.Items.Ref), item, path+key+"[][].")
+				}
+			}
+		}
+	}
+}
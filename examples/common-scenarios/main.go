@@ -140,58 +140,15 @@ func main() {
 				{Key: "Next Billing Date", Value: "February 1, 2025"},
 			},
 			Table: mailingo.Table{
-				Data: [][]mailingo.Entry{
-					// Header
-					{
-						{Key: "Description"},
-						{Key: "Quantity"},
-						{Key: "Unit Price"},
-						{Key: "Amount"},
-					},
-					// Items
-					{
-						{Value: "Pro Plan Subscription"},
-						{Value: "1"},
-						{Value: "$29.00"},
-						{Value: "$29.00"},
-					},
-					{
-						{Value: "Additional Users"},
-						{Value: "5"},
-						{Value: "$5.00"},
-						{Value: "$25.00"},
-					},
-					{
-						{Value: "API Calls (per 1000)"},
-						{Value: "150"},
-						{Value: "$0.10"},
-						{Value: "$15.00"},
-					},
-					{
-						{Value: "Storage Overage (GB)"},
-						{Value: "20"},
-						{Value: "$0.50"},
-						{Value: "$10.00"},
-					},
-					// Subtotal
-					{
-						{Value: "Subtotal"},
-						{Value: ""},
-						{Value: ""},
-						{Value: "$79.00"},
-					},
-					{
-						{Value: "Tax (10%)"},
-						{Value: ""},
-						{Value: ""},
-						{Value: "$7.90"},
-					},
-					{
-						{Value: "Total Amount"},
-						{Value: ""},
-						{Value: ""},
-						{Value: "$86.90"},
-					},
+				Header: []string{"Description", "Quantity", "Unit Price", "Amount"},
+				Rows: []mailingo.TableRow{
+					{Cells: []string{"Pro Plan Subscription", "1", "$29.00", "$29.00"}},
+					{Cells: []string{"Additional Users", "5", "$5.00", "$25.00"}},
+					{Cells: []string{"API Calls (per 1000)", "150", "$0.10", "$15.00"}},
+					{Cells: []string{"Storage Overage (GB)", "20", "$0.50", "$10.00"}},
+					{Cells: []string{"Subtotal", "", "", "$79.00"}},
+					{Cells: []string{"Tax (10%)", "", "", "$7.90"}},
+					{Cells: []string{"Total Amount", "", "", "$86.90"}},
 				},
 			},
 			Actions: []mailingo.Action{
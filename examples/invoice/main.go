@@ -43,29 +43,11 @@ func main() {
 				{Key: "Total Amount", Value: "$149.97"},
 			},
 			Table: mailingo.Table{
-				Data: [][]mailingo.Entry{
-					// Header row
-					{
-						{Key: "Product"},
-						{Key: "Quantity"},
-						{Key: "Price"},
-					},
-					// Data rows
-					{
-						{Value: "Wireless Headphones"},
-						{Value: "1"},
-						{Value: "$79.99"},
-					},
-					{
-						{Value: "Phone Case"},
-						{Value: "2"},
-						{Value: "$19.99"},
-					},
-					{
-						{Value: "USB Cable"},
-						{Value: "3"},
-						{Value: "$9.99"},
-					},
+				Header: []string{"Product", "Quantity", "Price"},
+				Rows: []mailingo.TableRow{
+					{Cells: []string{"Wireless Headphones", "1", "$79.99"}},
+					{Cells: []string{"Phone Case", "2", "$19.99"}},
+					{Cells: []string{"USB Cable", "3", "$9.99"}},
 				},
 			},
 			Actions: []mailingo.Action{
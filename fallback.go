@@ -0,0 +1,326 @@
+package mailingo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// fallbackChains parses the chains configured with options.WithFallback into the
+// languages looked up for each language, in order: the language itself, its
+// fallbacks followed transitively, and the default language English.
+func fallbackChains(fallbacks map[string][]string) (map[string][]language.Tag, error) {
+	parsed := make(map[language.Tag][]language.Tag, len(fallbacks))
+	for lang, chain := range fallbacks {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, fmt.Errorf("invalid language tag %q: %w", lang, err)
+		}
+		for _, fallback := range chain {
+			fallbackTag, err := language.Parse(fallback)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fallback %q of %q: %w", fallback, lang, err)
+			}
+			parsed[tag] = append(parsed[tag], fallbackTag)
+		}
+	}
+
+	chains := make(map[string][]language.Tag, len(parsed))
+	for tag := range parsed {
+		var chain []language.Tag
+		var walk func(language.Tag)
+		walk = func(tag language.Tag) {
+			if slices.Contains(chain, tag) {
+				return
+			}
+			chain = append(chain, tag)
+			for _, fallback := range parsed[tag] {
+				walk(fallback)
+			}
+		}
+		walk(tag)
+		if !slices.Contains(chain, language.English) {
+			chain = append(chain, language.English)
+		}
+		chains[tag.String()] = chain
+	}
+	return chains, nil
+}
+
+// exactCatalogs holds the loaded messages of each language in a bundle of their own,
+// so fallback chains can look up languages exactly: go-i18n's language matching
+// treats e.g. "en-x-acme" as "en" and "es-AR" as "es-MX". It is nil, and adding
+// messages does nothing, if no fallback chains are configured.
+type exactCatalogs map[language.Tag]*i18n.Bundle
+
+// newExactCatalogs returns empty catalogs, or nil without fallback chains
+func newExactCatalogs(fallbacks map[string][]language.Tag) exactCatalogs {
+	if len(fallbacks) == 0 {
+		return nil
+	}
+	return make(exactCatalogs)
+}
+
+// add adds messages of a language
+func (c exactCatalogs) add(tag language.Tag, messages []*i18n.Message) error {
+	if c == nil {
+		return nil
+	}
+	bundle, ok := c[tag]
+	if !ok {
+		bundle = i18n.NewBundle(tag)
+		c[tag] = bundle
+	}
+	return bundle.AddMessages(tag, messages...)
+}
+
+// chainLink is a language of a fallback chain with a localizer for its catalog
+type chainLink struct {
+	tag       language.Tag
+	localizer *i18n.Localizer // Nil if no messages of the language are loaded
+}
+
+// newChain creates the localizers of a fallback chain.
+// The caller must hold m.mu for reading.
+func (m *Mailer) newChain(tags []language.Tag) []chainLink {
+	chain := make([]chainLink, len(tags))
+	for i, tag := range tags {
+		chain[i].tag = tag
+		if bundle, ok := m.catalogs[tag]; ok {
+			chain[i].localizer = i18n.NewLocalizer(bundle, tag.String())
+		}
+	}
+	return chain
+}
+
+// canonicalLanguage returns the canonical form of a language tag, e.g. "es-AR" for
+// "es-ar", to look up fallback chains; invalid tags are returned unchanged
+func canonicalLanguage(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return lang
+	}
+	return tag.String()
+}
+
+// canonicalPreferences returns the canonical form of a language tag or Accept-Language
+// list to key caches, e.g. "fr-CH,fr" for "fr-ch, fr;q=0.9". A list that does not parse
+// returns the empty string, which is localized like it into the default language.
+func canonicalPreferences(lang string) string {
+	tags, _, err := language.ParseAcceptLanguage(lang)
+	if err != nil {
+		return ""
+	}
+	preferences := make([]string, len(tags))
+	for i, tag := range tags {
+		preferences[i] = tag.String()
+	}
+	return strings.Join(preferences, ",")
+}
+
+// matchLanguage returns the loaded language that go-i18n matches lang to.
+// The caller must hold m.mu for reading.
+func (m *Mailer) matchLanguage(lang string) language.Tag {
+	loaded := m.bundle.LanguageTags()
+	tags, _, _ := language.ParseAcceptLanguage(lang)
+	_, index, _ := language.NewMatcher(loaded).Match(tags...)
+	return loaded[index]
+}
+
+// builtinLanguage returns the language of the built-in translations used for messages
+// missing from the loaded catalogs: the best match of the preferred languages among the
+// loaded and built-in languages, so languages without message files use their built-in
+// translations, and built-in translations otherwise follow the language the rest of
+// the email resolves to. The caller must hold m.mu for reading.
+func (m *Mailer) builtinLanguage(preferred []language.Tag) language.Tag {
+	tags := m.bundle.LanguageTags()
+	for _, tag := range defaultLanguages() {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	_, index, _ := language.NewMatcher(tags).Match(preferred...)
+	return tags[index]
+}
+
+// otherLanguage reports whether a message resolved from the catalog of tag is in
+// another language than the built-in translations of the localizer, e.g. an English
+// message that go-i18n matched for "fr". Built-in translations beat such messages,
+// so loaded messages only take precedence within their own language.
+func (l *localizer) otherLanguage(tag language.Tag) bool {
+	base, _ := tag.Base()
+	builtin, _ := l.builtin.Base()
+	return base != builtin
+}
+
+// resolution is a resolved message
+type resolution struct {
+	text     string
+	tag      language.Tag // Language of the catalog the message came from
+	id       string       // Message ID of the variant used
+	override bool         // Whether the message came from the overrides of a tenant view
+}
+
+// localize resolves a message and returns the language of the catalog it came from.
+// A missing message returns an *i18n.MessageNotFoundErr.
+func (l *localizer) localize(config *i18n.LocalizeConfig) (string, language.Tag, error) {
+	r, err := l.resolve(config)
+	return r.text, r.tag, err
+}
+
+// resolve resolves a message from the tenant overrides, then the loaded catalogs.
+// With a fallback chain, the catalog of each language is looked up exactly and the
+// first one containing the message wins; otherwise go-i18n's language matching is used.
+// Each catalog is searched for the recipient's variants of the message before the
+// neutral message, so the neutral message of an earlier catalog beats a variant of
+// a later one, e.g. of the English catalog ending every fallback chain.
+func (l *localizer) resolve(config *i18n.LocalizeConfig) (resolution, error) {
+	ids := l.variantIDs(config.MessageID)
+
+	for _, link := range l.overrides {
+		for _, id := range ids {
+			if result, err := link.localizer.Localize(withMessageID(config, id)); err == nil {
+				return resolution{text: result, tag: link.tag, id: id, override: true}, nil
+			}
+		}
+	}
+
+	if l.chain == nil {
+		var err error
+		for _, id := range ids {
+			r := resolution{id: id}
+			if r.text, r.tag, err = l.LocalizeWithTag(withMessageID(config, id)); err == nil {
+				return r, nil
+			}
+		}
+		return resolution{}, err
+	}
+
+	for _, link := range l.chain {
+		if link.localizer == nil {
+			continue
+		}
+		for _, id := range ids {
+			if result, err := link.localizer.Localize(withMessageID(config, id)); err == nil {
+				return resolution{text: result, tag: link.tag, id: id}, nil
+			}
+		}
+	}
+	return resolution{}, &i18n.MessageNotFoundErr{Tag: l.chain[0].tag, MessageID: config.MessageID}
+}
+
+// withMessageID returns the config for another message ID
+func withMessageID(config *i18n.LocalizeConfig, id string) *i18n.LocalizeConfig {
+	if id == config.MessageID {
+		return config
+	}
+	c := *config
+	c.MessageID = id
+	return &c
+}
+
+// MessageTrace describes how a message ID was resolved for a language
+type MessageTrace struct {
+	MessageID string   // The message ID
+	Lang      string   // The requested language
+	Chain     []string // Catalog languages looked up, in order
+	Catalog   string   // Language of the catalog the message came from, empty if not found
+	Builtin   bool     // Whether the message came from the built-in translations
+	Override  bool     // Whether the message came from the overrides of a tenant view
+	Variant   string   // Message ID of the recipient's variant used (e.g., "greeting.formal"), empty for the neutral message
+	Text      string   // Translated text, or the message ID if not found
+}
+
+// Found reports whether the message was found in a loaded or built-in catalog
+func (t MessageTrace) Found() bool {
+	return t.Catalog != ""
+}
+
+// String formats the trace, e.g. `"greeting" es-AR: es (chain es-AR, es, en)`
+func (t MessageTrace) String() string {
+	source := t.Catalog
+	switch {
+	case !t.Found():
+		source = "missing"
+	case t.Builtin:
+		source = "built-in " + t.Catalog
+	case t.Override:
+		source = "tenant " + t.Catalog
+	}
+	if t.Variant != "" {
+		source += fmt.Sprintf(" variant %q", t.Variant)
+	}
+	return fmt.Sprintf("%q %s: %s (chain %s)", t.MessageID, t.Lang, source, strings.Join(t.Chain, ", "))
+}
+
+// TraceMessage translates a message ID like Translate and reports which catalog
+// it came from, to debug fallback chains and missing translations.
+//
+// Example:
+//
+//	trace := mailer.TraceMessage("greeting", "es-AR", nil)
+//	fmt.Println(trace) // "greeting" es-AR: es (chain es-AR, es, en)
+func (m *Mailer) TraceMessage(messageID, lang string, data map[string]interface{}) MessageTrace {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.traceMessage(m.localizer(lang), messageID, lang, data)
+}
+
+// TraceEmail traces every message ID the email looks up (see MessageIDs) for lang.
+// Variants are not traced separately: the trace of each message reports the variant
+// selected by Body.Formality and Body.Gender, if one was used.
+func (m *Mailer) TraceEmail(email Email, lang string) []MessageTrace {
+	ids := m.neutralMessageIDs(email)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	localizer := m.localizer(lang).withVariants(email.Body)
+	traces := make([]MessageTrace, len(ids))
+	for i, id := range ids {
+		traces[i] = m.traceMessage(localizer, id, lang, email.Data)
+	}
+	return traces
+}
+
+// traceMessage resolves a message ID like translate, recording where it came from.
+// The caller must hold m.mu for reading.
+func (m *Mailer) traceMessage(localizer *localizer, messageID, lang string, data map[string]interface{}) MessageTrace {
+	trace := MessageTrace{MessageID: messageID, Lang: lang, Text: messageID}
+	for _, tag := range localizer.lookupOrder() {
+		trace.Chain = append(trace.Chain, tag.String())
+	}
+
+	r, err := localizer.resolve(&i18n.LocalizeConfig{MessageID: messageID, TemplateData: data})
+	if err == nil && localizer.otherLanguage(r.tag) || errors.As(err, new(*i18n.MessageNotFoundErr)) {
+		// Built-in translations beat messages of another language, see translate
+		if result, tag, ok := defaultMessage(localizer.builtin, messageID, m.defaultMessageData(data)); ok {
+			trace.Catalog, trace.Builtin, trace.Text = tag.String(), true, localizer.pseudo.apply(result)
+			return trace
+		}
+	}
+	if err == nil {
+		trace.Catalog, trace.Override, trace.Text = r.tag.String(), r.override, localizer.pseudo.apply(r.text)
+		if r.id != messageID {
+			trace.Variant = r.id
+		}
+	}
+	return trace
+}
+
+// lookupOrder returns the catalog languages the localizer looks up, in order.
+// Without a fallback chain, only the matched language is used.
+func (l *localizer) lookupOrder() []language.Tag {
+	if l.chain == nil {
+		return []language.Tag{l.tag}
+	}
+
+	tags := make([]language.Tag, len(l.chain))
+	for i, link := range l.chain {
+		tags[i] = link.tag
+	}
+	return tags
+}
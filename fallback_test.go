@@ -0,0 +1,116 @@
+package mailingo
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestFallbackChain(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme,
+		options.WithFallback("es-AR", "es"),
+		options.WithFallback("en-x-acme", "en"))
+
+	locales := fstest.MapFS{
+		"en.json":        {Data: []byte(`{"email.title": "Welcome", "email.intro": "Thanks for signing up", "email.outro": "See you soon", "cart.items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}}`)},
+		"es.json":        {Data: []byte(`{"email.title": "Bienvenido", "email.intro": "Gracias por registrarte", "cart.items": {"one": "{{.Count}} artículo", "other": "{{.Count}} artículos"}}`)},
+		"es-MX.json":     {Data: []byte(`{"email.title": "Bienvenido a México", "cart.items": {"one": "{{.Count}} producto", "other": "{{.Count}} productos"}}`)},
+		"en-x-acme.json": {Data: []byte(`{"email.title": "Welcome to Acme"}`)},
+	}
+	for _, name := range []string{"en.json", "es.json", "es-MX.json", "en-x-acme.json"} {
+		if err := mailer.LoadMessageFileFS(locales, name); err != nil {
+			t.Fatalf("LoadMessageFileFS(%s) failed: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		lang, id, want string
+	}{
+		{"es-AR", "email.title", "Bienvenido"},
+		{"es-ar", "email.intro", "Gracias por registrarte"},
+		{"es-AR", "email.outro", "See you soon"},
+		{"es-AR", "greeting", "Hola"},
+		{"en-x-acme", "email.title", "Welcome to Acme"},
+		{"en-x-acme", "email.intro", "Thanks for signing up"},
+		{"es-MX", "email.title", "Bienvenido a México"},
+	}
+	for _, tt := range tests {
+		if got := mailer.Translate(tt.id, tt.lang, nil); got != tt.want {
+			t.Errorf("Translate(%q, %q): expected %q, got %q", tt.id, tt.lang, tt.want, got)
+		}
+	}
+
+	// Plural messages follow the same chains
+	plurals := []struct {
+		lang, want string
+	}{
+		{"es-AR", "2 artículos"},
+		{"es-MX", "2 productos"},
+		{"en-x-acme", "2 items"},
+	}
+	for _, tt := range plurals {
+		if got := mailer.TranslatePlural("cart.items", tt.lang, 2, nil); got != tt.want {
+			t.Errorf("TranslatePlural(%q): expected %q, got %q", tt.lang, tt.want, got)
+		}
+	}
+}
+
+func TestFallbackChainTransitive(t *testing.T) {
+	chains, err := fallbackChains(map[string][]string{
+		"es-AR":  {"es-419"},
+		"es-419": {"es", "es-AR"},
+	})
+	if err != nil {
+		t.Fatalf("fallbackChains failed: %v", err)
+	}
+
+	var got []string
+	for _, tag := range chains["es-AR"] {
+		got = append(got, tag.String())
+	}
+	if want := []string{"es-AR", "es-419", "es", "en"}; !slices.Equal(got, want) {
+		t.Errorf("Expected chain %v, got %v", want, got)
+	}
+
+	if _, err := fallbackChains(map[string][]string{"es-AR": {"not a tag"}}); err == nil {
+		t.Error("Invalid fallback should fail")
+	}
+}
+
+func TestTraceMessage(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme, options.WithFallback("es-AR", "es"))
+	if err := mailer.AddMessages("en", map[string]string{"email.title": "Welcome", "email.outro": "See you soon"}); err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+	if err := mailer.AddMessages("es", map[string]string{"email.title": "Bienvenido", "email.intro": "Gracias por registrarte"}); err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+	if err := mailer.AddMessages("es-MX", map[string]string{"email.title": "Bienvenido a México"}); err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	tests := []struct {
+		lang, id string
+		want     MessageTrace
+	}{
+		{"es-AR", "email.intro", MessageTrace{Chain: []string{"es-AR", "es", "en"}, Catalog: "es", Text: "Gracias por registrarte"}},
+		{"es-AR", "email.outro", MessageTrace{Chain: []string{"es-AR", "es", "en"}, Catalog: "en", Text: "See you soon"}},
+		{"es-AR", "signature", MessageTrace{Chain: []string{"es-AR", "es", "en"}, Catalog: "es", Builtin: true, Text: "Saludos cordiales"}},
+		{"es-MX", "email.intro", MessageTrace{Chain: []string{"es-MX"}, Text: "email.intro"}},
+		{"fr", "email.title", MessageTrace{Chain: []string{"en"}, Catalog: "en", Text: "Welcome"}},
+	}
+	for _, tt := range tests {
+		got := mailer.TraceMessage(tt.id, tt.lang, nil)
+		tt.want.MessageID, tt.want.Lang = tt.id, tt.lang
+		if got.String() != tt.want.String() || got.Text != tt.want.Text || got.Builtin != tt.want.Builtin {
+			t.Errorf("TraceMessage(%q, %q): expected %v %q, got %v %q", tt.id, tt.lang, tt.want, tt.want.Text, got, got.Text)
+		}
+	}
+
+	traces := mailer.TraceEmail(Email{Body: Body{Title: "email.title"}}, "es-AR")
+	if len(traces) != 4 || traces[0].MessageID != "email.title" || traces[0].Catalog != "es" {
+		t.Errorf("Unexpected email traces %v", traces)
+	}
+}
@@ -0,0 +1,414 @@
+package mailingo
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"strconv"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// maxCachedTemplates bounds the cache of templates bound to a language to the most
+// recently used languages, since lang may come from untrusted input
+const maxCachedTemplates = 64
+
+// dateLayouts are the default formatDate layouts by language or language and region.
+// Languages without an entry use ISO 8601 dates.
+var dateLayouts = map[string]string{
+	"en":    "01/02/2006",
+	"en-GB": "02/01/2006",
+	"en-AU": "02/01/2006",
+	"en-IN": "02/01/2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"ru":    "02.01.2006",
+	"pl":    "02.01.2006",
+	"tr":    "02.01.2006",
+	"ar":    "02/01/2006",
+	"zh":    "2006/01/02",
+	"ja":    "2006/01/02",
+	"ko":    "2006. 01. 02.",
+}
+
+// TemplateFuncs returns the built-in template functions, for parsing templates that are
+// passed to options.WithCustomTemplate or options.WithLayout and use them. At render time
+// the functions are bound to the language of the email being rendered:
+//
+//	t "key" [data]            translates a message ID, with optional template data
+//	tn "key" count [data]     translates a message ID with plural forms for count
+//	formatMoney amount "EUR"  formats an amount in a currency for the language
+//	formatDate date [layout]  formats a time.Time or RFC 3339 string, by default as a numeric date for the language
+//
+// Like the rest of the email, t and tn look up the variants selected by Body.Formality
+// and Body.Gender. Templates given as strings or files are parsed with these functions
+// automatically.
+//
+// Example:
+//
+//	tmpl := template.Must(template.New("email").Funcs(mailingo.TemplateFuncs()).Parse(
+//	    `<p>{{t "order.total"}}: {{formatMoney .Data.Total "EUR"}}</p>`))
+//	mailer := mailingo.New(product, theme, options.WithCustomTemplate(tmpl))
+func TemplateFuncs() template.FuncMap {
+	return builtinFuncs(nil, language.English)
+}
+
+// parseFuncs returns the functions available when parsing templates: the built-in
+// functions and the functions registered with options.WithFuncMap, which take precedence
+func parseFuncs(funcMap template.FuncMap) template.FuncMap {
+	funcs := TemplateFuncs()
+	maps.Copy(funcs, funcMap)
+	return funcs
+}
+
+// PlainTextTemplateFuncs returns the built-in template functions of TemplateFuncs and
+// textTable, which formats .Body.Table with aligned columns, for parsing plain text
+// templates that are passed to options.WithPlainTextTemplate.
+//
+// Example:
+//
+//	tmpl := texttemplate.Must(texttemplate.New("email").Funcs(mailingo.PlainTextTemplateFuncs()).Parse(
+//	    "{{t \"order.summary\"}}\n\n{{textTable .Body.Table}}"))
+//	mailer := mailingo.New(product, theme, options.WithPlainTextTemplate(tmpl))
+func PlainTextTemplateFuncs() texttemplate.FuncMap {
+	funcs := texttemplate.FuncMap(TemplateFuncs())
+	funcs["textTable"] = plainTextTable
+	return funcs
+}
+
+// parseTextFuncs returns the functions available when parsing plain text templates:
+// the built-in functions and the functions registered with options.WithFuncMap
+func parseTextFuncs(funcMap template.FuncMap) texttemplate.FuncMap {
+	funcs := PlainTextTemplateFuncs()
+	maps.Copy(funcs, funcMap)
+	return funcs
+}
+
+// builtinFuncs returns the built-in template functions bound to the language, whose
+// t and tn translate with the binding. Without a binding, t and tn return the message ID.
+func builtinFuncs(binding *templateBinding, tag language.Tag) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, data ...map[string]interface{}) string {
+			if binding == nil {
+				return key
+			}
+			return binding.translate(key, firstData(data))
+		},
+		"tn": func(key string, count interface{}, data ...map[string]interface{}) string {
+			if binding == nil {
+				return key
+			}
+			return binding.translatePlural(key, count, firstData(data))
+		},
+		"formatMoney": func(amount interface{}, code string) (string, error) {
+			return formatMoney(tag, amount, code)
+		},
+		"formatDate": func(date interface{}, layout ...string) (string, error) {
+			return formatDate(tag, date, layout...)
+		},
+	}
+}
+
+// firstData returns the optional template data argument
+func firstData(data []map[string]interface{}) map[string]interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return data[0]
+}
+
+// TranslatePlural translates a message ID with plural forms into lang, selecting the
+// form for count. The count is also available to the message as {{.Count}}, unless
+// data sets it. If the message is not found, the message ID itself is returned.
+//
+// Example:
+//
+//	// en.json: {"cart.items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}}
+//	mailer.TranslatePlural("cart.items", "en", 3, nil) // "3 items"
+func (m *Mailer) TranslatePlural(messageID, lang string, count interface{}, data map[string]interface{}) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.translatePlural(m.localizer(lang), messageID, count, data)
+}
+
+// translatePlural translates a message ID with plural forms like TranslatePlural.
+// The caller must hold m.mu for reading.
+func (m *Mailer) translatePlural(localizer *localizer, messageID string, count interface{}, data map[string]interface{}) string {
+	if messageID == "" {
+		return ""
+	}
+
+	// go-i18n accepts integers and decimal strings as plural counts,
+	// so floats, e.g. from JSON data, are passed as strings
+	switch n := count.(type) {
+	case float64:
+		count = strconv.FormatFloat(n, 'f', -1, 64)
+	case float32:
+		count = strconv.FormatFloat(float64(n), 'f', -1, 32)
+	case json.Number:
+		count = n.String()
+	}
+
+	templateData := map[string]interface{}{"Count": count}
+	maps.Copy(templateData, data)
+
+	result, _, err := localizer.localize(&i18n.LocalizeConfig{
+		MessageID:    messageID,
+		PluralCount:  count,
+		TemplateData: templateData,
+	})
+	if err != nil {
+		return messageID
+	}
+	return localizer.pseudo.apply(result)
+}
+
+// formatMoney formats an amount in the currency with the ISO 4217 code for the language
+func formatMoney(tag language.Tag, amount interface{}, code string) (string, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("formatMoney: invalid currency %q", code)
+	}
+	value, err := toFloat(amount)
+	if err != nil {
+		return "", fmt.Errorf("formatMoney: %w", err)
+	}
+	return message.NewPrinter(tag).Sprint(currency.Symbol(unit.Amount(value))), nil
+}
+
+// formatDate formats a time.Time or RFC 3339 string with the layout,
+// defaulting to the numeric date layout of the language
+func formatDate(tag language.Tag, date interface{}, layout ...string) (string, error) {
+	var t time.Time
+	switch v := date.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		t = *v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if parsed, err = time.Parse(time.DateOnly, v); err != nil {
+				return "", fmt.Errorf("formatDate: invalid date %q", v)
+			}
+		}
+		t = parsed
+	default:
+		return "", fmt.Errorf("formatDate: unsupported value of type %T", date)
+	}
+
+	if len(layout) > 0 && layout[0] != "" {
+		return t.Format(layout[0]), nil
+	}
+	return t.Format(dateLayout(tag)), nil
+}
+
+// dateLayout returns the default date layout for the language,
+// preferring an entry for the language and region
+func dateLayout(tag language.Tag) string {
+	base, _ := tag.Base()
+	if region, confidence := tag.Region(); confidence == language.Exact {
+		if layout, ok := dateLayouts[base.String()+"-"+region.String()]; ok {
+			return layout
+		}
+	}
+	if layout, ok := dateLayouts[base.String()]; ok {
+		return layout
+	}
+	return time.DateOnly
+}
+
+// toFloat converts a numeric template value to float64
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("unsupported amount of type %T", v)
+	}
+}
+
+// templateBinding is the mailer and localizer that the t and tn functions of a
+// bound template translate with, set by the render executing the template
+type templateBinding struct {
+	mailer    *Mailer
+	localizer *localizer
+}
+
+// translate translates a message ID like Mailer.Translate
+func (b *templateBinding) translate(key string, data map[string]interface{}) string {
+	b.mailer.mu.RLock()
+	defer b.mailer.mu.RUnlock()
+	return b.mailer.translate(b.localizer, key, "", data)
+}
+
+// translatePlural translates a message ID like Mailer.TranslatePlural
+func (b *templateBinding) translatePlural(key string, count interface{}, data map[string]interface{}) string {
+	b.mailer.mu.RLock()
+	defer b.mailer.mu.RUnlock()
+	return b.mailer.translatePlural(b.localizer, key, count, data)
+}
+
+// executor is an HTML or plain text template
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// boundTemplate is a clone of a template whose built-in functions are bound to a
+// language and translate with its binding
+type boundTemplate[T executor] struct {
+	tmpl    T
+	binding *templateBinding
+}
+
+// templatePool keeps the clones of a template bound to a language for reuse, since
+// cloning and escaping a template is expensive. A clone is used by one render at a
+// time, so the t and tn functions translate with the mailer and localizer of the
+// render, and tenant views and recipients with variants can share the clones.
+type templatePool[T executor] struct {
+	mu    sync.Mutex
+	free  []boundTemplate[T]
+	clone func(binding *templateBinding) (T, error)
+}
+
+// execute executes a clone of the template bound to the mailer and localizer. The
+// templates are never executed themselves, so they can be cloned unless they were
+// executed before being passed to the Mailer.
+func (p *templatePool[T]) execute(w io.Writer, data interface{}, m *Mailer, localizer *localizer) error {
+	p.mu.Lock()
+	var bound boundTemplate[T]
+	if n := len(p.free); n > 0 {
+		bound = p.free[n-1]
+		p.free = p.free[:n-1]
+	}
+	p.mu.Unlock()
+
+	if bound.binding == nil {
+		bound.binding = &templateBinding{}
+		var err error
+		if bound.tmpl, err = p.clone(bound.binding); err != nil {
+			return fmt.Errorf("failed to bind template functions: %w", err)
+		}
+	}
+
+	bound.binding.mailer, bound.binding.localizer = m, localizer
+	err := bound.tmpl.Execute(w, data)
+	*bound.binding = templateBinding{}
+
+	p.mu.Lock()
+	p.free = append(p.free, bound)
+	p.mu.Unlock()
+	return err
+}
+
+// boundTemplateKey identifies a template bound to a language
+type boundTemplateKey struct {
+	tmpl *template.Template
+	lang string // Canonical language tag
+}
+
+// boundTemplate returns the pool of clones of tmpl whose built-in functions are
+// bound to lang. The pools are shared by the Mailer and its tenant views, and keyed by
+// the canonical language tag, so spellings of the same language share them.
+// The caller must hold m.mu for reading.
+func (s *shared) boundTemplate(tmpl *template.Template, lang string) *templatePool[*template.Template] {
+	tag := language.Make(lang)
+	key := boundTemplateKey{tmpl: tmpl, lang: tag.String()}
+
+	s.poolMu.Lock()
+	defer s.poolMu.Unlock()
+
+	s.syncPools()
+	if pool, ok := s.boundTemplates.get(key); ok {
+		return pool
+	}
+
+	pool := &templatePool[*template.Template]{
+		clone: func(binding *templateBinding) (*template.Template, error) {
+			bound, err := tmpl.Clone()
+			if err != nil {
+				return nil, err
+			}
+			return bound.Funcs(builtinFuncs(binding, tag)).Funcs(s.funcMap), nil
+		},
+	}
+	s.boundTemplates.add(key, pool)
+	return pool
+}
+
+// boundTextTemplateKey identifies a plain text template bound to a language
+type boundTextTemplateKey struct {
+	tmpl *texttemplate.Template
+	lang string // Canonical language tag
+}
+
+// boundTextTemplate returns the pool of clones of the plain text template whose
+// built-in functions are bound to lang, like boundTemplate.
+// The caller must hold m.mu for reading.
+func (s *shared) boundTextTemplate(tmpl *texttemplate.Template, lang string) *templatePool[*texttemplate.Template] {
+	tag := language.Make(lang)
+	key := boundTextTemplateKey{tmpl: tmpl, lang: tag.String()}
+
+	s.poolMu.Lock()
+	defer s.poolMu.Unlock()
+
+	s.syncPools()
+	if pool, ok := s.boundTextTemplates.get(key); ok {
+		return pool
+	}
+
+	pool := &templatePool[*texttemplate.Template]{
+		clone: func(binding *templateBinding) (*texttemplate.Template, error) {
+			bound, err := tmpl.Clone()
+			if err != nil {
+				return nil, err
+			}
+			return bound.Funcs(texttemplate.FuncMap(builtinFuncs(binding, tag))).Funcs(texttemplate.FuncMap(s.funcMap)), nil
+		},
+	}
+	s.boundTextTemplates.add(key, pool)
+	return pool
+}
+
+// syncPools drops the pools of bound templates if the template changed since they
+// were created. The caller must hold s.mu for reading and s.poolMu.
+func (s *shared) syncPools() {
+	if s.poolGeneration != s.generation {
+		s.boundTemplates.clear()
+		s.boundTextTemplates.clear()
+		s.poolGeneration = s.generation
+	}
+}
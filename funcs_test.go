@@ -0,0 +1,166 @@
+package mailingo
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme, options.WithCustomTemplateString(
+		`{{t "order.total"}}|{{t "order.greeting" .Data}}|{{formatMoney .Data.Amount "EUR"}}|{{formatDate .Data.Date}}|{{formatDate .Data.Date "2006"}}`,
+	))
+
+	err := mailer.AddMessages("en", map[string]string{"order.total": "Total", "order.greeting": "Hi {{.Name}}"})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+	err = mailer.AddMessages("de", map[string]string{"order.total": "Summe", "order.greeting": "Hallo {{.Name}}"})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	email := Email{Data: map[string]interface{}{
+		"Name":   "Jane",
+		"Amount": 1234.5,
+		"Date":   time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+	}}
+
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"en", "Total|Hi Jane|€ 1,234.50|03/14/2025|2025"},
+		{"en-GB", "Total|Hi Jane|€ 1,234.50|14/03/2025|2025"},
+		{"de", "Summe|Hallo Jane|€ 1.234,50|14.03.2025|2025"},
+		{"ja", "Total|Hi Jane|€ 1,234.50|2025/03/14|2025"},
+	}
+
+	for _, tt := range tests {
+		html, err := mailer.GenerateHTML(email, tt.lang)
+		if err != nil {
+			t.Fatalf("GenerateHTML in %s failed: %v", tt.lang, err)
+		}
+		if html != tt.want {
+			t.Errorf("In %s, expected %q, got %q", tt.lang, tt.want, html)
+		}
+	}
+}
+
+func TestTemplateFuncsPlural(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme, options.WithCustomTemplateString(`{{tn "cart.items" .Data.Count}}`))
+
+	err := mailer.LoadMessageFileFS(fstest.MapFS{
+		"en.json": {Data: []byte(`{"cart.items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}}`)},
+	}, "en.json")
+	if err != nil {
+		t.Fatalf("LoadMessageFileFS failed: %v", err)
+	}
+
+	for count, want := range map[interface{}]string{1: "1 item", 3: "3 items", 2.0: "2 items"} {
+		html, err := mailer.GenerateHTML(Email{Data: map[string]interface{}{"Count": count}}, "en")
+		if err != nil {
+			t.Fatalf("GenerateHTML failed: %v", err)
+		}
+		if html != want {
+			t.Errorf("Count %v: expected %q, got %q", count, want, html)
+		}
+	}
+
+	if got := mailer.TranslatePlural("missing", "en", 1, nil); got != "missing" {
+		t.Errorf("Missing plural message should return the ID, got %q", got)
+	}
+}
+
+func TestTemplateFuncsErrors(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme, options.WithCustomTemplateString(`{{formatMoney .Data.Amount "XXXX"}}`))
+	if _, err := mailer.GenerateHTML(Email{Data: map[string]interface{}{"Amount": 1}}, "en"); err == nil {
+		t.Error("Invalid currency should fail rendering")
+	}
+
+	mailer = New(Product{Name: "Acme"}, DefaultTheme, options.WithCustomTemplateString(`{{formatDate .Data.Date}}`))
+	if _, err := mailer.GenerateHTML(Email{Data: map[string]interface{}{"Date": "yesterday"}}, "en"); err == nil {
+		t.Error("Invalid date should fail rendering")
+	}
+	html, err := mailer.GenerateHTML(Email{Data: map[string]interface{}{"Date": "2025-03-14"}}, "fr")
+	if err != nil || html != "14/03/2025" {
+		t.Errorf("Date strings should be parsed, got %q (%v)", html, err)
+	}
+}
+
+func TestWithFuncMap(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme,
+		options.WithFuncMap(template.FuncMap{"upper": strings.ToUpper}),
+		options.WithFuncMap(template.FuncMap{"t": func(key string) string { return "custom:" + key }}),
+		options.WithCustomTemplateString(`{{upper .Product.Name}} {{t "order.total"}}`),
+	)
+
+	html, err := mailer.GenerateHTML(Email{}, "de")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if html != "ACME custom:order.total" {
+		t.Errorf("Custom functions should be available and replace built-ins, got %q", html)
+	}
+}
+
+func TestTemplateFuncsPreParsed(t *testing.T) {
+	tmpl := template.Must(template.New("email").Funcs(TemplateFuncs()).Parse(`{{t "order.total"}}`))
+	mailer := New(Product{}, DefaultTheme, options.WithCustomTemplate(tmpl))
+	if err := mailer.AddMessages("de", map[string]string{"order.total": "Summe"}); err != nil {
+		t.Fatal(err)
+	}
+
+	html, err := mailer.GenerateHTML(Email{}, "de")
+	if err != nil || html != "Summe" {
+		t.Errorf("Pre-parsed templates should use the bound functions, got %q (%v)", html, err)
+	}
+}
+
+func TestTemplateFuncsExecutedTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("email").Funcs(TemplateFuncs()).Parse(`{{t "order.total"}}`))
+	if err := tmpl.Execute(io.Discard, nil); err != nil {
+		t.Fatal(err)
+	}
+	mailer := New(Product{}, DefaultTheme, options.WithCustomTemplate(tmpl))
+
+	// An executed template cannot be bound to the language, so rendering fails
+	// instead of leaving t untranslated
+	if _, err := mailer.GenerateHTML(Email{}, "en"); err == nil {
+		t.Error("Executed templates should fail rendering")
+	}
+}
+
+func TestBoundTemplateCache(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme, options.WithCustomTemplateString(`{{formatDate .Data.Date}}`))
+	email := Email{Data: map[string]interface{}{"Date": "2025-03-14"}}
+
+	// Spellings of the same language share the bound templates
+	for _, lang := range []string{"fr-ch", "fr-CH", "FR-ch"} {
+		if _, err := mailer.GenerateHTML(email, lang); err != nil {
+			t.Fatalf("GenerateHTML failed: %v", err)
+		}
+	}
+	if n := mailer.boundTemplates.len(); n != 1 {
+		t.Errorf("Expected 1 cached template, got %d", n)
+	}
+
+	// Many distinct languages evict the least recently used templates
+	for i := range maxCachedTemplates * 2 {
+		mailer.GenerateHTML(email, fmt.Sprintf("en-x-lang%d", i))
+		mailer.GenerateHTML(email, "fr-CH")
+	}
+	if n := mailer.boundTemplates.len(); n != maxCachedTemplates {
+		t.Errorf("Expected %d cached templates, got %d", maxCachedTemplates, n)
+	}
+	html, err := mailer.GenerateHTML(email, "fr-ch")
+	if err != nil || html != "14/03/2025" {
+		t.Errorf("Expected French date, got %q (%v)", html, err)
+	}
+}
@@ -6,3 +6,5 @@ require (
 	github.com/nicksnyder/go-i18n/v2 v2.6.0
 	golang.org/x/text v0.30.0
 )
+
+require gopkg.in/yaml.v3 v3.0.1
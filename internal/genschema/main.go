@@ -0,0 +1,24 @@
+// Command genschema writes the JSON Schema of mailingo.Email.
+// It is run by go generate in the repository root.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/lib-x/mailingo"
+)
+
+func main() {
+	output := flag.String("o", "email.schema.json", "Output file")
+	flag.Parse()
+
+	schema, err := mailingo.JSONSchema()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, append(schema, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,288 @@
+package mailingo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// LintKind is the kind of problem found by LintLocales
+type LintKind string
+
+const (
+	LintMissing      LintKind = "missing"      // Message of the reference locale is missing
+	LintExtra        LintKind = "extra"        // Message does not exist in the reference locale
+	LintPlaceholders LintKind = "placeholders" // Template placeholders differ from the reference locale
+	LintPlural       LintKind = "plural"       // Plural categories required by the language are missing
+)
+
+// LintIssue is a problem found in a message catalog
+type LintIssue struct {
+	File      string   // Message file with the problem (e.g., "zh.json")
+	Lang      string   // Language of the message file
+	MessageID string   // ID of the affected message
+	Kind      LintKind // Kind of problem
+	Detail    string   // Human-readable description
+}
+
+// String formats the issue as "file: kind message-id: detail"
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s %q: %s", i.File, i.Kind, i.MessageID, i.Detail)
+}
+
+// LintReport lists the problems found by LintLocales
+type LintReport struct {
+	Reference string      // Language of the reference catalog
+	Files     []string    // Checked message files
+	Issues    []LintIssue // Problems, sorted by file, kind and message ID
+}
+
+// OK reports whether no problems were found
+func (r *LintReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// catalog is a parsed message file
+type catalog struct {
+	file     string
+	tag      language.Tag
+	messages map[string]*i18n.Message
+}
+
+// LintLocales compares the .json message files in the root of fsys against the
+// catalog of the reference language (e.g., "en") and reports:
+//
+//   - messages of the reference catalog missing from another catalog
+//   - messages that do not exist in the reference catalog
+//   - messages whose template placeholders (e.g., {{.Name}}) differ from the reference
+//   - plural messages lacking a CLDR plural category required by their language,
+//     e.g. "few" and "many" in Russian; such messages fail to render for some counts
+//
+// It returns an error if a file cannot be parsed or no file has the reference language.
+//
+// Example:
+//
+//	report, err := mailingo.LintLocales(os.DirFS("locales"), "en")
+//	for _, issue := range report.Issues {
+//	    fmt.Println(issue)
+//	}
+func LintLocales(fsys fs.FS, reference string) (*LintReport, error) {
+	refTag, err := language.Parse(reference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference language %q: %w", reference, err)
+	}
+
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	unmarshalFuncs := map[string]i18n.UnmarshalFunc{"json": json.Unmarshal}
+	var catalogs []*catalog
+	var ref *catalog
+	for _, file := range files {
+		if strings.HasPrefix(path.Base(file), ".") {
+			continue
+		}
+		buf, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		messageFile, err := i18n.ParseMessageFileBytes(buf, file, unmarshalFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse message file %s: %w", file, err)
+		}
+
+		c := &catalog{file: file, tag: messageFile.Tag, messages: make(map[string]*i18n.Message, len(messageFile.Messages))}
+		for _, message := range messageFile.Messages {
+			c.messages[message.ID] = message
+		}
+		catalogs = append(catalogs, c)
+		if c.tag == refTag && ref == nil {
+			ref = c
+		}
+	}
+	if ref == nil {
+		return nil, fmt.Errorf("no message file for reference language %q", reference)
+	}
+
+	report := &LintReport{Reference: refTag.String(), Files: files}
+	for _, c := range catalogs {
+		add := func(id string, kind LintKind, format string, args ...interface{}) {
+			report.Issues = append(report.Issues, LintIssue{
+				File:      c.file,
+				Lang:      c.tag.String(),
+				MessageID: id,
+				Kind:      kind,
+				Detail:    fmt.Sprintf(format, args...),
+			})
+		}
+
+		for id, message := range c.messages {
+			if missing := missingPluralForms(c.tag, message, ref.messages[id]); len(missing) > 0 {
+				add(id, LintPlural, "missing plural categories %s required by %s", strings.Join(missing, ", "), c.tag)
+			}
+		}
+		if c == ref {
+			continue
+		}
+
+		for id, refMessage := range ref.messages {
+			message, ok := c.messages[id]
+			if !ok {
+				add(id, LintMissing, "not translated from %s", ref.file)
+				continue
+			}
+			want, got := placeholders(refMessage), placeholders(message)
+			if !slices.Equal(want, got) {
+				add(id, LintPlaceholders, "uses %s, %s uses %s", formatPlaceholders(got), ref.file, formatPlaceholders(want))
+			}
+		}
+		for id := range c.messages {
+			if _, ok := ref.messages[id]; !ok {
+				add(id, LintExtra, "not in %s", ref.file)
+			}
+		}
+	}
+
+	kindOrder := map[LintKind]int{LintMissing: 0, LintExtra: 1, LintPlaceholders: 2, LintPlural: 3}
+	sort.Slice(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.MessageID < b.MessageID
+	})
+	return report, nil
+}
+
+// pluralForms returns the texts of the message by CLDR plural category
+func pluralForms(message *i18n.Message) map[string]string {
+	return map[string]string{
+		"zero":  message.Zero,
+		"one":   message.One,
+		"two":   message.Two,
+		"few":   message.Few,
+		"many":  message.Many,
+		"other": message.Other,
+	}
+}
+
+// isPlural reports whether the message has plural forms besides "other"
+func isPlural(message *i18n.Message) bool {
+	return message != nil && message.Zero+message.One+message.Two+message.Few+message.Many != ""
+}
+
+// missingPluralForms returns the plural categories required by the language that the
+// message lacks, if the message or its reference is a plural message
+func missingPluralForms(tag language.Tag, message, reference *i18n.Message) []string {
+	if !isPlural(message) && !isPlural(reference) {
+		return nil
+	}
+
+	forms := pluralForms(message)
+	var missing []string
+	for _, category := range requiredPluralForms(tag) {
+		if forms[category] == "" {
+			missing = append(missing, category)
+		}
+	}
+	return missing
+}
+
+// pluralCategories are the CLDR plural categories in canonical order
+var pluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+// pluralSamples are counts covering every plural category of the CLDR rules
+var pluralSamples = func() []string {
+	var samples []string
+	for i := 0; i <= 200; i++ {
+		samples = append(samples, strconv.Itoa(i))
+	}
+	return append(samples, "1000", "1000000", "0.0", "0.5", "1.0", "1.5", "2.5", "10.1", "100.0")
+}()
+
+// requiredPluralCache caches requiredPluralForms by language
+var requiredPluralCache sync.Map
+
+// requiredPluralForms returns the plural categories the language needs, in canonical order.
+// They are derived from the plural rules go-i18n uses at render time, by localizing a
+// message with every category for sample counts and recording the categories selected.
+func requiredPluralForms(tag language.Tag) []string {
+	if forms, ok := requiredPluralCache.Load(tag); ok {
+		return forms.([]string)
+	}
+
+	message := &i18n.Message{ID: "plural", Zero: "zero", One: "one", Two: "two", Few: "few", Many: "many", Other: "other"}
+	bundle := i18n.NewBundle(tag)
+	if err := bundle.AddMessages(tag, message); err != nil {
+		// go-i18n has no plural rule for the language, so only "other" is ever used
+		return []string{"other"}
+	}
+	localizer := i18n.NewLocalizer(bundle, tag.String())
+
+	used := map[string]bool{"other": true}
+	for _, count := range pluralSamples {
+		form, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: "plural", PluralCount: count})
+		if err == nil {
+			used[form] = true
+		}
+	}
+
+	var forms []string
+	for _, category := range pluralCategories {
+		if used[category] {
+			forms = append(forms, category)
+		}
+	}
+	requiredPluralCache.Store(tag, forms)
+	return forms
+}
+
+// actionPattern matches template actions and placeholderPattern the data fields
+// used in them, e.g. ".Name" in "{{.Name}}"
+var (
+	actionPattern      = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	placeholderPattern = regexp.MustCompile(`\.[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*`)
+)
+
+// placeholders returns the sorted, unique data fields used by all forms of the message
+func placeholders(message *i18n.Message) []string {
+	seen := map[string]bool{}
+	for _, text := range pluralForms(message) {
+		for _, action := range actionPattern.FindAllStringSubmatch(text, -1) {
+			for _, field := range placeholderPattern.FindAllString(action[1], -1) {
+				seen[field] = true
+			}
+		}
+	}
+
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// formatPlaceholders formats placeholders for an issue description
+func formatPlaceholders(fields []string) string {
+	if len(fields) == 0 {
+		return "no placeholders"
+	}
+	return "{{" + strings.Join(fields, "}}, {{") + "}}"
+}
@@ -0,0 +1,91 @@
+package mailingo
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestLintLocales(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.json": {Data: []byte(`{
+			"greeting": "Hello",
+			"order.total": "Total: {{.Amount}}",
+			"cart.items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}
+		}`)},
+		"de.json": {Data: []byte(`{
+			"greeting": "Hallo",
+			"order.total": "Summe: {{.Total}}",
+			"cart.items": {"one": "Ein Artikel", "other": "{{.Count}} Artikel"}
+		}`)},
+		"ru.json": {Data: []byte(`{
+			"greeting": "Здравствуйте",
+			"cart.items": {"one": "{{.Count}} товар", "other": "{{.Count}} товара"},
+			"order.discount": "Скидка"
+		}`)},
+	}
+
+	report, err := LintLocales(fsys, "en")
+	if err != nil {
+		t.Fatalf("LintLocales failed: %v", err)
+	}
+
+	want := []LintIssue{
+		{File: "de.json", Lang: "de", MessageID: "order.total", Kind: LintPlaceholders, Detail: "uses {{.Total}}, en.json uses {{.Amount}}"},
+		{File: "ru.json", Lang: "ru", MessageID: "order.total", Kind: LintMissing, Detail: "not translated from en.json"},
+		{File: "ru.json", Lang: "ru", MessageID: "order.discount", Kind: LintExtra, Detail: "not in en.json"},
+		{File: "ru.json", Lang: "ru", MessageID: "cart.items", Kind: LintPlural, Detail: "missing plural categories few, many required by ru"},
+	}
+
+	if len(report.Issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(want), len(report.Issues), report.Issues)
+	}
+	for i, issue := range report.Issues {
+		if issue != want[i] {
+			t.Errorf("Issue %d: expected %v, got %v", i, want[i], issue)
+		}
+	}
+	if report.OK() {
+		t.Error("Report with issues should not be OK")
+	}
+}
+
+func TestLintLocalesClean(t *testing.T) {
+	report, err := LintLocales(os.DirFS("testdata"), "en")
+	if err != nil {
+		t.Fatalf("LintLocales failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("testdata catalogs should be consistent, got %v", report.Issues)
+	}
+	if len(report.Files) != 2 {
+		t.Errorf("Expected 2 checked files, got %v", report.Files)
+	}
+}
+
+func TestLintLocalesErrors(t *testing.T) {
+	if _, err := LintLocales(fstest.MapFS{"de.json": {Data: []byte(`{}`)}}, "en"); err == nil {
+		t.Error("Missing reference catalog should fail")
+	}
+	if _, err := LintLocales(fstest.MapFS{"en.json": {Data: []byte(`not json`)}}, "en"); err == nil {
+		t.Error("Invalid message file should fail")
+	}
+}
+
+func TestRequiredPluralForms(t *testing.T) {
+	tests := map[string]string{
+		"en": "[one other]",
+		"fr": "[one many other]",
+		"ru": "[one few many other]",
+		"ar": "[zero one two few many other]",
+		"zh": "[other]",
+	}
+	for lang, want := range tests {
+		if got := fmt.Sprint(requiredPluralForms(language.Make(lang))); got != want {
+			t.Errorf("%s: expected %s, got %s", lang, want, got)
+		}
+	}
+}
@@ -1,114 +1,175 @@
 package mailingo
 
 import (
-	"bytes"
+	"bufio"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	texttemplate "text/template"
 
 	"github.com/lib-x/mailingo/options"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 )
 
-//go:embed templates/*.html
+//go:embed templates/*.html templates/*.txt
 var templatesFS embed.FS
 
-// Mailer is a multi-language email generator that supports i18n
+// Mailer is a multi-language email generator that supports i18n.
+// A Mailer is safe for concurrent use by multiple goroutines, including
+// loading messages while emails are being rendered.
 type Mailer struct {
-	bundle    *i18n.Bundle
+	*shared // Messages and templates, shared with tenant views
+
+	cacheMu         sync.Mutex // Guards cacheGeneration and localizers
+	cacheGeneration uint64     // Generation of the shared state the localizers were created for
+	localizers      *lruCache[string, *localizer]
+
 	product   Product
 	theme     Theme
-	template  *template.Template
 	customCSS string
+
+	overrides       exactCatalogs                  // Message overrides of a tenant view by exact language, immutable
+	overrideSources []map[string]map[string]string // Message overrides the tenant view was created from
+
+	trackingURL                 string
+	trackingSuppressedLanguages []string
+	trackingSuppressor          func(lang, trackingID string) bool
+
+	validate      bool
+	validateLinks bool
+	linkPolicy    LinkPolicy
+
+	sizeLimits SizeLimits
+}
+
+// shared is the state of a Mailer that its tenant views share
+type shared struct {
+	mu         sync.RWMutex // Guards bundle, catalogs, template and generation
+	bundle     *i18n.Bundle
+	catalogs   exactCatalogs // Messages by exact language for the fallback chains
+	template   *template.Template
+	generation uint64 // Incremented whenever the messages or the template change
+
+	layouts      map[string]*template.Template // Named layouts, immutable after New
+	blocks       map[string]string             // Block overrides applied to the template
+	funcMap      template.FuncMap              // Template functions registered with options.WithFuncMap
+	textTemplate *texttemplate.Template        // Plain text template, immutable after New
+	fallbacks    map[string][]language.Tag     // Fallback chains by language, immutable after New
+
+	poolMu             sync.Mutex // Guards poolGeneration, boundTemplates and boundTextTemplates
+	poolGeneration     uint64     // Generation of the template the pools were created for
+	boundTemplates     *lruCache[boundTemplateKey, *templatePool[*template.Template]]
+	boundTextTemplates *lruCache[boundTextTemplateKey, *templatePool[*texttemplate.Template]]
 }
 
 // Product represents the product/company information displayed in emails
 type Product struct {
-	Name      string // Product or company name
-	Link      string // Product or company website URL
-	Logo      string // URL to the logo image
-	Copyright string // Copyright text (supports i18n key, e.g., "product.copyright")
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`           // Product or company name
+	Link      string `json:"link,omitempty" yaml:"link,omitempty"`           // Product or company website URL
+	Logo      string `json:"logo,omitempty" yaml:"logo,omitempty"`           // URL to the logo image
+	Copyright string `json:"copyright,omitempty" yaml:"copyright,omitempty"` // Copyright text (supports i18n key, e.g., "product.copyright")
 }
 
 // Theme defines the color scheme and styling for the email
 type Theme struct {
-	PrimaryColor    string // Primary brand color
-	BackgroundColor string // Email background color
-	TextColor       string // Main text color
-	ButtonColor     string // Button background color
-	ButtonTextColor string // Button text color
+	PrimaryColor    string `json:"primary_color,omitempty" yaml:"primary_color,omitempty"`         // Primary brand color
+	BackgroundColor string `json:"background_color,omitempty" yaml:"background_color,omitempty"`   // Email background color
+	TextColor       string `json:"text_color,omitempty" yaml:"text_color,omitempty"`               // Main text color
+	ButtonColor     string `json:"button_color,omitempty" yaml:"button_color,omitempty"`           // Button background color
+	ButtonTextColor string `json:"button_text_color,omitempty" yaml:"button_text_color,omitempty"` // Button text color
 }
 
 // Email represents the complete email structure
 type Email struct {
-	Body            Body             // Email body content
-	SMTPAttachments []SMTPAttachment // Files to be attached when sending via SMTP (not rendered in template)
+	Body            Body                   `json:"body,omitzero" yaml:"body,omitempty"`                          // Email body content
+	SMTPAttachments []SMTPAttachment       `json:"smtp_attachments,omitempty" yaml:"smtp_attachments,omitempty"` // Files to be attached when sending via SMTP (not rendered in template)
+	TrackingID      string                 `json:"tracking_id,omitempty" yaml:"tracking_id,omitempty"`           // Per-message ID used for the open tracking pixel (optional)
+	DisableTracking bool                   `json:"disable_tracking,omitempty" yaml:"disable_tracking,omitempty"` // Suppresses the tracking pixel for this email, e.g. for recipients without consent
+	Data            map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`                         // Template data for translated messages (e.g., "Order {{.OrderID}}") and custom templates
+	Layout          string                 `json:"layout,omitempty" yaml:"layout,omitempty"`                     // Name of a layout registered with options.WithLayout (defaults to the Mailer's template)
 }
 
 // Body contains the main content of the email
 type Body struct {
-	Name        string       // Recipient's name
-	Intros      []string     // Introduction paragraphs (supports i18n keys)
-	Dictionary  []Entry      // Key-value pairs for structured information
-	Table       Table        // Table data
-	Actions     []Action     // Action buttons
-	Outros      []string     // Closing paragraphs (supports i18n keys)
-	Attachments []Attachment // List of attachments with download links
-	Greeting    string       // Greeting text (supports i18n key, defaults to "greeting")
-	Signature   string       // Signature text (supports i18n key, defaults to "signature")
-	Title       string       // Email title (supports i18n key)
+	Name        string       `json:"name,omitempty" yaml:"name,omitempty"`               // Recipient's name
+	Intros      []string     `json:"intros,omitempty" yaml:"intros,omitempty"`           // Introduction paragraphs (supports i18n keys)
+	Dictionary  []Entry      `json:"dictionary,omitempty" yaml:"dictionary,omitempty"`   // Key-value pairs for structured information
+	Table       Table        `json:"table,omitzero" yaml:"table,omitempty"`              // Table data
+	Actions     []Action     `json:"actions,omitempty" yaml:"actions,omitempty"`         // Action buttons
+	Outros      []string     `json:"outros,omitempty" yaml:"outros,omitempty"`           // Closing paragraphs (supports i18n keys)
+	Attachments []Attachment `json:"attachments,omitempty" yaml:"attachments,omitempty"` // List of attachments with download links
+	Greeting    string       `json:"greeting,omitempty" yaml:"greeting,omitempty"`       // Greeting text (supports i18n key, defaults to "greeting")
+	Signature   string       `json:"signature,omitempty" yaml:"signature,omitempty"`     // Signature text (supports i18n key, defaults to "signature")
+	Title       string       `json:"title,omitempty" yaml:"title,omitempty"`             // Email title (supports i18n key)
+	Formality   string       `json:"formality,omitempty" yaml:"formality,omitempty"`     // Formality of the address (e.g., FormalityFormal), selects message variants
+	Gender      string       `json:"gender,omitempty" yaml:"gender,omitempty"`           // Grammatical gender of the recipient (e.g., GenderFeminine), selects message variants
 }
 
 // Entry represents a key-value pair entry
 type Entry struct {
-	Key   string // Key text (supports i18n key)
-	Value string // Value text
+	Key   string `json:"key,omitempty" yaml:"key,omitempty"`     // Key text (supports i18n key)
+	Value string `json:"value,omitempty" yaml:"value,omitempty"` // Value text
 }
 
-// Table represents tabular data in the email
+// Table represents tabular data in the email.
+// Prefer Header and Rows; Data is kept for compatibility and cannot be combined with them.
 type Table struct {
-	Data    [][]Entry // Table rows, first row is treated as headers
-	Columns Columns   // Column definitions
+	Header    []string   `json:"header,omitempty" yaml:"header,omitempty"`         // Header cells (supports i18n keys)
+	Rows      []TableRow `json:"rows,omitempty" yaml:"rows,omitempty"`             // Body rows, each with one cell per header cell
+	KeyHeader string     `json:"key_header,omitempty" yaml:"key_header,omitempty"` // Header of the row key column, used when rows have keys (supports i18n key)
+	Data      [][]Entry  `json:"data,omitempty" yaml:"data,omitempty"`             // Deprecated: table rows, first row is treated as headers. Use Header and Rows.
+	Columns   Columns    `json:"columns,omitzero" yaml:"columns,omitempty"`        // Column definitions
+}
+
+// TableRow represents a body row of a Table
+type TableRow struct {
+	Key   string   `json:"key,omitempty" yaml:"key,omitempty"`     // Optional row label rendered as a leading header cell (supports i18n key)
+	Cells []string `json:"cells,omitempty" yaml:"cells,omitempty"` // Cell values
 }
 
 // Columns defines custom column properties
 type Columns struct {
-	CustomWidth     map[string]string // Custom column widths (e.g., "50%", "100px")
-	CustomAlignment map[string]string // Custom column alignments (e.g., "left", "center", "right")
+	CustomWidth     map[string]string `json:"custom_width,omitempty" yaml:"custom_width,omitempty"`         // Custom column widths (e.g., "50%", "100px")
+	CustomAlignment map[string]string `json:"custom_alignment,omitempty" yaml:"custom_alignment,omitempty"` // Custom column alignments (e.g., "left", "center", "right")
 }
 
 // Action represents a call-to-action button with instructions
 type Action struct {
-	Instructions   string // Instruction text above the button (supports i18n key)
-	Button         Button // The action button
-	InvertedButton bool   // Whether to use inverted button style (outlined)
+	Instructions   string `json:"instructions,omitempty" yaml:"instructions,omitempty"`       // Instruction text above the button (supports i18n key)
+	Button         Button `json:"button,omitzero" yaml:"button,omitempty"`                    // The action button
+	InvertedButton bool   `json:"inverted_button,omitempty" yaml:"inverted_button,omitempty"` // Whether to use inverted button style (outlined)
 }
 
 // Button represents a clickable button in the email
 type Button struct {
-	Text  string // Button text (supports i18n key)
-	Link  string // Button URL
-	Color string // Custom button color (optional, overrides theme color)
+	Text  string `json:"text,omitempty" yaml:"text,omitempty"`   // Button text (supports i18n key)
+	Link  string `json:"link,omitempty" yaml:"link,omitempty"`   // Button URL
+	Color string `json:"color,omitempty" yaml:"color,omitempty"` // Custom button color (optional, overrides theme color)
 }
 
 // Attachment represents a file attachment that can be downloaded
 type Attachment struct {
-	Name string // File name (e.g., "invoice.pdf")
-	URL  string // Download URL for the attachment
-	Size string // Human-readable file size (e.g., "2.5 MB")
-	Type string // File type or description (e.g., "PDF Document", "Excel Spreadsheet")
+	Name string `json:"name,omitempty" yaml:"name,omitempty"` // File name (e.g., "invoice.pdf")
+	URL  string `json:"url,omitempty" yaml:"url,omitempty"`   // Download URL for the attachment
+	Size string `json:"size,omitempty" yaml:"size,omitempty"` // Human-readable file size (e.g., "2.5 MB")
+	Type string `json:"type,omitempty" yaml:"type,omitempty"` // File type or description (e.g., "PDF Document", "Excel Spreadsheet")
 }
 
 // SMTPAttachment represents an actual file to be attached to the email when sending via SMTP.
 // This is separate from Attachment which only displays download links in the email body.
 // Use this with your SMTP sending library (e.g., gomail, go-mail, etc.)
 type SMTPAttachment struct {
-	Filename    string // Name of the file as it will appear in the email
-	Content     []byte // File content bytes
-	ContentType string // MIME type (e.g., "application/pdf", "image/png")
+	Filename    string `json:"filename,omitempty" yaml:"filename,omitempty"`         // Name of the file as it will appear in the email
+	Content     []byte `json:"content,omitempty" yaml:"content,omitempty"`           // File content bytes, base64-encoded in JSON and YAML
+	ContentType string `json:"content_type,omitempty" yaml:"content_type,omitempty"` // MIME type (e.g., "application/pdf", "image/png")
 }
 
 // DefaultTheme is the default color theme (similar to Hermes default theme)
@@ -141,8 +202,7 @@ var FlatTheme = Theme{
 //
 //	mailer := mailingo.New(product, theme, options.WithCustomCSS("..."))
 func New(product Product, theme Theme, opts ...options.Option) *Mailer {
-	bundle := i18n.NewBundle(language.English)
-	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	bundle := newBundle()
 
 	// Apply options
 	config := &options.Config{}
@@ -153,162 +213,423 @@ func New(product Product, theme Theme, opts ...options.Option) *Mailer {
 	// Determine which template to use
 	var tmpl *template.Template
 	var err error
+	funcs := parseFuncs(config.FuncMap)
 
 	if config.CustomTemplate != nil {
 		// User provided a parsed template
 		tmpl = config.CustomTemplate
 	} else if config.CustomTemplateText != "" {
 		// User provided a template string
-		tmpl, err = template.New("email").Parse(config.CustomTemplateText)
+		tmpl, err = parseTemplate("email", config.CustomTemplateText, funcs)
 		if err != nil {
 			panic(fmt.Sprintf("failed to parse custom template: %v", err))
 		}
 	} else if config.CustomTemplateFS != nil && config.CustomTemplatePath != "" {
 		// User provided a template file from embedded FS
-		content, err := fs.ReadFile(config.CustomTemplateFS, config.CustomTemplatePath)
+		tmpl, err = parseTemplateFile("email", config.CustomTemplateFS, config.CustomTemplatePath, funcs)
+		if err != nil {
+			panic(fmt.Sprintf("failed to load custom template file: %v", err))
+		}
+	} else {
+		// Use default embedded template
+		tmpl, err = parseTemplateFile("email", templatesFS, "templates/default.html", funcs)
 		if err != nil {
-			panic(fmt.Sprintf("failed to read custom template file: %v", err))
+			panic(fmt.Sprintf("failed to load default template: %v", err))
 		}
-		tmpl, err = template.New("email").Parse(string(content))
+	}
+
+	// Override individual blocks, keeping the rest of the template
+	if tmpl, err = applyBlocks(tmpl, config.TemplateBlocks, funcs); err != nil {
+		panic(fmt.Sprintf("failed to override template block: %v", err))
+	}
+
+	// Determine which plain text template to use
+	var textTmpl *texttemplate.Template
+	textFuncs := parseTextFuncs(config.FuncMap)
+
+	if config.PlainTextTemplate != nil {
+		textTmpl = config.PlainTextTemplate
+	} else if config.PlainTextTemplateText != "" {
+		textTmpl, err = texttemplate.New("email").Funcs(textFuncs).Parse(config.PlainTextTemplateText)
 		if err != nil {
-			panic(fmt.Sprintf("failed to parse custom template file: %v", err))
+			panic(fmt.Sprintf("failed to parse plain text template: %v", err))
 		}
 	} else {
-		// Use default embedded template
-		content, err := fs.ReadFile(templatesFS, "templates/default.html")
+		textFS, textPath := fs.FS(templatesFS), "templates/default.txt"
+		if config.PlainTextTemplateFS != nil && config.PlainTextTemplatePath != "" {
+			textFS, textPath = config.PlainTextTemplateFS, config.PlainTextTemplatePath
+		}
+		content, err := fs.ReadFile(textFS, textPath)
 		if err != nil {
-			panic(fmt.Sprintf("failed to read default template: %v", err))
+			panic(fmt.Sprintf("failed to read plain text template: %v", err))
 		}
-		tmpl, err = template.New("email").Parse(string(content))
+		textTmpl, err = texttemplate.New("email").Funcs(textFuncs).Parse(string(content))
 		if err != nil {
-			panic(fmt.Sprintf("failed to parse default template: %v", err))
+			panic(fmt.Sprintf("failed to parse plain text template: %v", err))
 		}
 	}
 
+	fallbacks, err := fallbackChains(config.Fallbacks)
+	if err != nil {
+		panic(fmt.Sprintf("failed to configure fallbacks: %v", err))
+	}
+
+	// Named layouts selected with Email.Layout
+	layouts := make(map[string]*template.Template, len(config.Layouts))
+	for name, layout := range config.Layouts {
+		switch {
+		case layout.Template != nil:
+			layouts[name] = layout.Template
+		case layout.FS != nil:
+			layouts[name], err = parseTemplateFile(name, layout.FS, layout.Path, funcs)
+		default:
+			layouts[name], err = parseTemplate(name, layout.Text, funcs)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("failed to load layout %q: %v", name, err))
+		}
+	}
+
+	sizeLimits := DefaultSizeLimits
+	if config.HTMLSizeWarn != 0 || config.HTMLSizeMax != 0 {
+		sizeLimits.HTMLWarn = config.HTMLSizeWarn
+		sizeLimits.HTMLMax = config.HTMLSizeMax
+	}
+	sizeLimits.MessageWarn = config.MessageSizeWarn
+	sizeLimits.MessageMax = config.MessageSizeMax
+
 	return &Mailer{
-		bundle:    bundle,
+		shared: &shared{
+			bundle:   bundle,
+			catalogs: newExactCatalogs(fallbacks),
+			template: tmpl,
+
+			layouts:      layouts,
+			blocks:       config.TemplateBlocks,
+			funcMap:      config.FuncMap,
+			textTemplate: textTmpl,
+			fallbacks:    fallbacks,
+
+			boundTemplates:     newLRUCache[boundTemplateKey, *templatePool[*template.Template]](maxCachedTemplates),
+			boundTextTemplates: newLRUCache[boundTextTemplateKey, *templatePool[*texttemplate.Template]](maxCachedTemplates),
+		},
+		localizers: newLRUCache[string, *localizer](maxCachedLocalizers),
+
 		product:   product,
 		theme:     theme,
-		template:  tmpl,
 		customCSS: config.CustomCSS,
+
+		trackingURL:                 config.TrackingPixelURL,
+		trackingSuppressedLanguages: config.TrackingSuppressedLanguages,
+		trackingSuppressor:          config.TrackingSuppressor,
+
+		validate:      config.Validate,
+		validateLinks: config.ValidateLinks,
+		linkPolicy: LinkPolicy{
+			AllowInsecure: config.AllowInsecureLinks,
+			AllowRelative: config.AllowRelativeLinks,
+			AllowMailto:   config.AllowMailtoLinks,
+		},
+
+		sizeLimits: sizeLimits,
+	}
+}
+
+// parseTemplate parses an HTML email template with the template functions
+func parseTemplate(name, text string, funcs template.FuncMap) (*template.Template, error) {
+	return template.New(name).Funcs(funcs).Parse(text)
+}
+
+// parseTemplateFile reads and parses an HTML email template from a filesystem
+func parseTemplateFile(name string, fsys fs.FS, path string, funcs template.FuncMap) (*template.Template, error) {
+	content, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTemplate(name, string(content), funcs)
+}
+
+// applyBlocks returns a copy of tmpl with the named blocks replaced.
+// The original template is left unchanged, as it may be shared by the caller.
+func applyBlocks(tmpl *template.Template, blocks map[string]string, funcs template.FuncMap) (*template.Template, error) {
+	if len(blocks) == 0 {
+		return tmpl, nil
+	}
+
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
 	}
+	clone.Funcs(funcs)
+	for name, text := range blocks {
+		if clone.Lookup(name) == nil {
+			return nil, fmt.Errorf("template does not define block %q", name)
+		}
+		if _, err := clone.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("block %q: %w", name, err)
+		}
+	}
+	return clone, nil
+}
+
+// layout returns the template selected by the email's layout, falling back to
+// the default template for an empty or unknown layout name.
+// The caller must hold m.mu for reading.
+func (m *Mailer) layout(email Email) *template.Template {
+	if tmpl, ok := m.layouts[email.Layout]; ok && email.Layout != "" {
+		return tmpl
+	}
+	return m.template
+}
+
+// HasLayout reports whether a layout with the given name was registered with options.WithLayout
+func (m *Mailer) HasLayout(name string) bool {
+	_, ok := m.layouts[name]
+	return ok
+}
+
+// newBundle creates an empty message bundle with English as default language
+func newBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	return bundle
 }
 
 // LoadMessageFile loads translation messages from a file.
 // The file format is determined by its extension (e.g., .json, .toml, .yaml).
 func (m *Mailer) LoadMessageFile(path string) error {
-	_, err := m.bundle.LoadMessageFile(path)
-	return err
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return m.parseMessageFileBytes(buf, path)
 }
 
 // LoadMessageFileFS loads translation messages from an embedded filesystem.
 // This is useful when you embed translation files using go:embed directive.
-func (m *Mailer) LoadMessageFileFS(fs fs.FS, path string) error {
-	_, err := m.bundle.LoadMessageFileFS(fs, path)
-	return err
+func (m *Mailer) LoadMessageFileFS(fsys fs.FS, path string) error {
+	buf, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return err
+	}
+	return m.parseMessageFileBytes(buf, path)
+}
+
+// AddMessages adds translation messages for a language at runtime.
+// The messages map message IDs to their translations; existing messages
+// with the same ID are replaced. It is safe to call while emails are being rendered.
+//
+// Example:
+//
+//	err := mailer.AddMessages("de", map[string]string{
+//	    "greeting":  "Hallo",
+//	    "signature": "Viele Grüße",
+//	})
+func (m *Mailer) AddMessages(lang string, messages map[string]string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return fmt.Errorf("invalid language tag %q: %w", lang, err)
+	}
+
+	msgs := make([]*i18n.Message, 0, len(messages))
+	for id, text := range messages {
+		msgs = append(msgs, &i18n.Message{ID: id, Other: text})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.bundle.AddMessages(tag, msgs...); err != nil {
+		return err
+	}
+	if err := m.catalogs.add(tag, msgs); err != nil {
+		return err
+	}
+	m.generation++
+	return nil
+}
+
+// parseMessageFileBytes parses a message file and adds its messages to the bundle
+func (m *Mailer) parseMessageFileBytes(buf []byte, path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	messageFile, err := m.bundle.ParseMessageFileBytes(buf, path)
+	if err != nil {
+		return err
+	}
+	if err := m.catalogs.add(messageFile.Tag, messageFile.Messages); err != nil {
+		return err
+	}
+	m.generation++
+	return nil
+}
+
+// maxCachedLocalizers bounds the localizer cache to the most recently used languages,
+// since lang may come from untrusted input
+const maxCachedLocalizers = 64
+
+// localizer translates messages into a language
+type localizer struct {
+	*i18n.Localizer
+	tag       language.Tag  // Language of the loaded catalog the messages resolve to
+	builtin   language.Tag  // Language of the built-in translations used for missing messages
+	chain     []chainLink   // Fallback chain configured with options.WithFallback, if any
+	overrides []chainLink   // Message overrides of a tenant view, looked up first
+	pseudo    *pseudoLocale // Transforms the resolved messages, for pseudo-locales only
+	variants  []string      // Message ID suffixes of the recipient's variants, most specific first
+}
+
+// localizer returns the cached localizer for lang, creating it if necessary.
+// Localizers are cached by the canonical form of lang, so spellings of the same
+// languages share them. The caller must hold m.mu for reading.
+func (m *Mailer) localizer(lang string) *localizer {
+	m.cacheMu.Lock()
+	defer m.cacheMu.Unlock()
+
+	m.syncCaches()
+	key := canonicalPreferences(lang)
+	l, ok := m.localizers.get(key)
+	if !ok {
+		l = &localizer{}
+		lang = key
+		if l.pseudo = findPseudoLocale(lang); l.pseudo != nil {
+			lang = l.pseudo.source
+		}
+		l.Localizer = i18n.NewLocalizer(m.bundle, lang)
+		l.tag = m.matchLanguage(lang)
+		preferred, _, _ := language.ParseAcceptLanguage(lang)
+		if tags, ok := m.fallbacks[canonicalLanguage(lang)]; ok && l.pseudo == nil {
+			l.chain = m.newChain(tags)
+			preferred = tags
+		}
+		l.builtin = m.builtinLanguage(preferred)
+		l.overrides = m.newOverrides(key, l.lookupOrder())
+		m.localizers.add(key, l)
+	}
+	return l
+}
+
+// syncCaches drops the cached localizers if the shared messages changed since they
+// were cached. The caller must hold m.mu for reading and m.cacheMu.
+func (m *Mailer) syncCaches() {
+	if m.cacheGeneration != m.generation {
+		m.localizers.clear()
+		m.cacheGeneration = m.generation
+	}
 }
 
 // GenerateHTML generates an HTML email from the given email structure and language.
 // The lang parameter should be a BCP 47 language tag (e.g., "en", "zh-CN").
+// If validation is enabled, a *ValidationError is returned for invalid emails.
 func (m *Mailer) GenerateHTML(email Email, lang string) (string, error) {
-	localizer := i18n.NewLocalizer(m.bundle, lang)
+	var buf strings.Builder
+	if err := m.RenderHTMLTo(&buf, email, lang); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderHTMLTo renders an HTML email like GenerateHTML, but writes it directly to w.
+// This avoids building the whole email in memory, e.g. when writing into an
+// SMTP DATA writer or an HTTP response. If rendering fails, w may have
+// received partial output.
+func (m *Mailer) RenderHTMLTo(w io.Writer, email Email, lang string) error {
+	if err := m.validateBeforeRender(email); err != nil {
+		return err
+	}
 
-	// Process all translations
-	data := m.processTranslations(email, localizer)
+	// Process all translations while holding the read lock,
+	// so rendering never observes a partially loaded bundle
+	m.mu.RLock()
+	localizer := m.localizer(lang).withVariants(email.Body)
+	translated := m.translateEmail(email, localizer)
+	tmpl := m.layout(email)
+	pool := m.boundTemplate(tmpl, lang)
+	m.mu.RUnlock()
+
+	data := m.processTranslations(email, translated)
+	data["TrackingPixel"] = m.trackingPixelURL(email, lang)
+	data["Direction"] = direction(lang)
 
 	// Render the HTML template
-	var buf bytes.Buffer
-	err := m.template.Execute(&buf, data)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute email template: %w", err)
+	buf := bufio.NewWriter(w)
+	if err := pool.execute(buf, data, m, localizer); err != nil {
+		return fmt.Errorf("failed to execute email template: %w", err)
 	}
-
-	return buf.String(), nil
+	return buf.Flush()
 }
 
 // GeneratePlainText generates a plain text email from the given email structure and language.
 // The lang parameter should be a BCP 47 language tag (e.g., "en", "zh-CN").
+// If validation is enabled, a *ValidationError is returned for invalid emails.
 func (m *Mailer) GeneratePlainText(email Email, lang string) (string, error) {
-	localizer := i18n.NewLocalizer(m.bundle, lang)
-
-	var buf bytes.Buffer
-
-	// Greeting
-	greeting := m.translate(localizer, email.Body.Greeting, "greeting")
-	buf.WriteString(fmt.Sprintf("%s %s,\n\n", greeting, email.Body.Name))
-
-	// Title
-	if email.Body.Title != "" {
-		title := m.translate(localizer, email.Body.Title, "")
-		buf.WriteString(fmt.Sprintf("%s\n\n", title))
-	}
-
-	// Introduction paragraphs
-	for _, intro := range email.Body.Intros {
-		text := m.translate(localizer, intro, "")
-		buf.WriteString(fmt.Sprintf("%s\n\n", text))
-	}
-
-	// Dictionary (key-value pairs)
-	for _, entry := range email.Body.Dictionary {
-		key := m.translate(localizer, entry.Key, "")
-		buf.WriteString(fmt.Sprintf("%s: %s\n", key, entry.Value))
-	}
-	if len(email.Body.Dictionary) > 0 {
-		buf.WriteString("\n")
-	}
-
-	// Actions
-	for _, action := range email.Body.Actions {
-		instructions := m.translate(localizer, action.Instructions, "")
-		buttonText := m.translate(localizer, action.Button.Text, "")
-		buf.WriteString(fmt.Sprintf("%s\n%s: %s\n\n", instructions, buttonText, action.Button.Link))
-	}
-
-	// Closing paragraphs
-	for _, outro := range email.Body.Outros {
-		text := m.translate(localizer, outro, "")
-		buf.WriteString(fmt.Sprintf("%s\n\n", text))
-	}
-
-	// Attachments
-	if len(email.Body.Attachments) > 0 {
-		buf.WriteString("Attachments:\n")
-		for _, attachment := range email.Body.Attachments {
-			buf.WriteString(fmt.Sprintf("  - %s", attachment.Name))
-			if attachment.Type != "" || attachment.Size != "" {
-				buf.WriteString(" (")
-				if attachment.Type != "" {
-					buf.WriteString(attachment.Type)
-				}
-				if attachment.Size != "" {
-					if attachment.Type != "" {
-						buf.WriteString(", ")
-					}
-					buf.WriteString(attachment.Size)
-				}
-				buf.WriteString(")")
-			}
-			buf.WriteString(fmt.Sprintf("\n    %s\n", attachment.URL))
-		}
-		buf.WriteString("\n")
+	var buf strings.Builder
+	if err := m.RenderTextTo(&buf, email, lang); err != nil {
+		return "", err
 	}
+	return buf.String(), nil
+}
 
-	// Signature
-	signature := m.translate(localizer, email.Body.Signature, "signature")
-	buf.WriteString(fmt.Sprintf("%s,\n%s\n\n", signature, m.product.Name))
+// RenderTextTo renders a plain text email like GeneratePlainText, but writes it directly to w.
+// If rendering fails, w may have received partial output.
+func (m *Mailer) RenderTextTo(w io.Writer, email Email, lang string) error {
+	if err := m.validateBeforeRender(email); err != nil {
+		return err
+	}
 
-	// Copyright
-	copyright := m.translate(localizer, m.product.Copyright, "product.copyright")
-	buf.WriteString(copyright)
+	m.mu.RLock()
+	localizer := m.localizer(lang).withVariants(email.Body)
+	translated := m.translateEmail(email, localizer)
+	pool := m.boundTextTemplate(m.textTemplate, lang)
+	m.mu.RUnlock()
 
-	return buf.String(), nil
+	data := m.processTranslations(email, translated)
+
+	buf := bufio.NewWriter(w)
+	if err := pool.execute(buf, data, m, localizer); err != nil {
+		return fmt.Errorf("failed to execute plain text template: %w", err)
+	}
+	return buf.Flush()
+}
+
+// Translate translates a single message ID into lang, e.g. for a subject line.
+// The data is passed to the message as template data. If the message is not
+// found, the message ID itself is returned.
+func (m *Mailer) Translate(messageID, lang string, data map[string]interface{}) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.translate(m.localizer(lang), messageID, "", data)
+}
+
+// Languages returns the BCP 47 tags of all languages with loaded messages,
+// including the default language English, in the order they were loaded.
+func (m *Mailer) Languages() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tags := m.bundle.LanguageTags()
+	langs := make([]string, len(tags))
+	for i, tag := range tags {
+		langs[i] = tag.String()
+	}
+	return langs
+}
+
+// validateBeforeRender runs the validation enabled with the options package
+func (m *Mailer) validateBeforeRender(email Email) error {
+	if m.validate {
+		return m.Validate(email)
+	}
+	if m.validateLinks {
+		return m.ValidateLinks(email)
+	}
+	return nil
 }
 
 // translate is a helper function that translates a message ID using the localizer.
 // If the key is empty and a defaultKey is provided, it uses the defaultKey.
+// The data is passed to the message as template data (e.g., "Order {{.OrderID}}").
 // If translation fails, it returns the original key as fallback.
-func (m *Mailer) translate(localizer *i18n.Localizer, key string, defaultKey string) string {
+func (m *Mailer) translate(localizer *localizer, key string, defaultKey string, data map[string]interface{}) string {
 	if key == "" && defaultKey != "" {
 		key = defaultKey
 	}
@@ -317,37 +638,74 @@ func (m *Mailer) translate(localizer *i18n.Localizer, key string, defaultKey str
 	}
 
 	// Try to localize the message
-	result, err := localizer.Localize(&i18n.LocalizeConfig{
-		MessageID: key,
+	r, err := localizer.resolve(&i18n.LocalizeConfig{
+		MessageID:    key,
+		TemplateData: data,
 	})
-	if err != nil {
-		// If translation fails, return the original key as fallback
-		return key
+	if err == nil && !localizer.otherLanguage(r.tag) {
+		return localizer.pseudo.apply(r.text)
+	}
+
+	// Fall back to the built-in translations, which also beat messages of another
+	// language that go-i18n's language matching resolved to
+	var notFound *i18n.MessageNotFoundErr
+	if err == nil || errors.As(err, &notFound) {
+		if result, _, ok := defaultMessage(localizer.builtin, key, m.defaultMessageData(data)); ok {
+			return localizer.pseudo.apply(result)
+		}
+	}
+	if err == nil {
+		return localizer.pseudo.apply(r.text)
 	}
-	return result
+
+	// If translation fails, return the original key as fallback
+	return key
+}
+
+// translatedEmail holds the translated content of an email, ready for rendering
+type translatedEmail struct {
+	Greeting    string
+	Signature   string
+	Title       string
+	Copyright   string
+	Intros      []string
+	Outros      []string
+	Dictionary  []Entry
+	Actions     []Action
+	Table       tableView
+	Attachments []Attachment
+	Labels      labels
 }
 
-// processTranslations processes all translations in the email structure
-func (m *Mailer) processTranslations(email Email, localizer *i18n.Localizer) map[string]interface{} {
+// labels are the translated labels of the default templates
+type labels struct {
+	Attachments string
+}
+
+// translateEmail translates all translatable fields of the email with the localizer
+// of the recipient's variants (see localizer.withVariants).
+// The caller must hold m.mu for reading.
+func (m *Mailer) translateEmail(email Email, localizer *localizer) translatedEmail {
 	body := email.Body
+	data := email.Data
 
 	// Translate introduction paragraphs
 	intros := make([]string, len(body.Intros))
 	for i, intro := range body.Intros {
-		intros[i] = m.translate(localizer, intro, "")
+		intros[i] = m.translate(localizer, intro, "", data)
 	}
 
 	// Translate closing paragraphs
 	outros := make([]string, len(body.Outros))
 	for i, outro := range body.Outros {
-		outros[i] = m.translate(localizer, outro, "")
+		outros[i] = m.translate(localizer, outro, "", data)
 	}
 
 	// Translate dictionary entries
 	dictionary := make([]Entry, len(body.Dictionary))
 	for i, entry := range body.Dictionary {
 		dictionary[i] = Entry{
-			Key:   m.translate(localizer, entry.Key, ""),
+			Key:   m.translate(localizer, entry.Key, "", data),
 			Value: entry.Value,
 		}
 	}
@@ -356,9 +714,9 @@ func (m *Mailer) processTranslations(email Email, localizer *i18n.Localizer) map
 	actions := make([]Action, len(body.Actions))
 	for i, action := range body.Actions {
 		actions[i] = Action{
-			Instructions: m.translate(localizer, action.Instructions, ""),
+			Instructions: m.translate(localizer, action.Instructions, "", data),
 			Button: Button{
-				Text:  m.translate(localizer, action.Button.Text, ""),
+				Text:  m.translate(localizer, action.Button.Text, "", data),
 				Link:  action.Button.Link,
 				Color: action.Button.Color,
 			},
@@ -366,47 +724,56 @@ func (m *Mailer) processTranslations(email Email, localizer *i18n.Localizer) map
 		}
 	}
 
-	// Translate table data
-	tableData := make([][]Entry, len(body.Table.Data))
-	for i, row := range body.Table.Data {
-		tableData[i] = make([]Entry, len(row))
-		for j, cell := range row {
-			tableData[i][j] = Entry{
-				Key:   m.translate(localizer, cell.Key, ""),
-				Value: cell.Value,
-			}
-		}
-	}
-
 	// Process attachments (no translation needed, but include for consistency)
 	attachments := make([]Attachment, len(body.Attachments))
-	for i, attachment := range body.Attachments {
-		attachments[i] = attachment
+	copy(attachments, body.Attachments)
+
+	return translatedEmail{
+		Greeting:    m.translate(localizer, body.Greeting, MessageGreeting, data),
+		Signature:   m.translate(localizer, body.Signature, MessageSignature, data),
+		Title:       m.translate(localizer, body.Title, "", data),
+		Copyright:   m.translate(localizer, m.product.Copyright, MessageCopyright, data),
+		Intros:      intros,
+		Outros:      outros,
+		Dictionary:  dictionary,
+		Actions:     actions,
+		Table:       m.processTable(body.Table, localizer, data),
+		Attachments: attachments,
+		Labels: labels{
+			Attachments: m.translate(localizer, MessageAttachments, "", data),
+		},
 	}
+}
+
+// processTranslations builds the template data from the translated email
+func (m *Mailer) processTranslations(email Email, translated translatedEmail) map[string]interface{} {
+	table := translated.Table
+	table.Columns = email.Body.Table.Columns
 
 	return map[string]interface{}{
 		"Product": map[string]interface{}{
 			"Name":      m.product.Name,
 			"Link":      m.product.Link,
 			"Logo":      m.product.Logo,
-			"Copyright": m.translate(localizer, m.product.Copyright, "product.copyright"),
+			"Copyright": translated.Copyright,
 		},
 		"Theme":     m.theme,
 		"CustomCSS": template.CSS(m.customCSS), // Use template.CSS for CSS context
+		"Data":      email.Data,
 		"Body": map[string]interface{}{
-			"Name":       body.Name,
-			"Greeting":   m.translate(localizer, body.Greeting, "greeting"),
-			"Signature":  m.translate(localizer, body.Signature, "signature"),
-			"Title":      m.translate(localizer, body.Title, ""),
-			"Intros":     intros,
-			"Dictionary": dictionary,
-			"Table": map[string]interface{}{
-				"Data":    tableData,
-				"Columns": body.Table.Columns,
-			},
-			"Actions":     actions,
-			"Outros":      outros,
-			"Attachments": attachments,
+			"Name":        email.Body.Name,
+			"Greeting":    translated.Greeting,
+			"Signature":   translated.Signature,
+			"Title":       translated.Title,
+			"Intros":      translated.Intros,
+			"Dictionary":  translated.Dictionary,
+			"Table":       table,
+			"Actions":     translated.Actions,
+			"Outros":      translated.Outros,
+			"Attachments": translated.Attachments,
+			"Formality":   email.Body.Formality,
+			"Gender":      email.Body.Gender,
 		},
+		"Labels": translated.Labels,
 	}
 }
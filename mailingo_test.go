@@ -2,9 +2,16 @@ package mailingo
 
 import (
 	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	texttemplate "text/template"
 
 	"github.com/lib-x/mailingo/options"
 )
@@ -817,3 +824,423 @@ func TestDefaultTemplateWithoutOptions(t *testing.T) {
 		t.Error("HTML should contain recipient name")
 	}
 }
+
+func TestRenderTo(t *testing.T) {
+	product := Product{
+		Name:      "Acme Corporation",
+		Link:      "https://acme.com",
+		Copyright: "product.copyright",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Name:   "Jane Smith",
+			Intros: []string{"Welcome aboard."},
+		},
+	}
+
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	var htmlBuf strings.Builder
+	if err := mailer.RenderHTMLTo(&htmlBuf, email, "en"); err != nil {
+		t.Fatalf("RenderHTMLTo failed: %v", err)
+	}
+
+	if htmlBuf.String() != html {
+		t.Error("RenderHTMLTo should write the same HTML as GenerateHTML")
+	}
+
+	var writes writeCounter
+	if err := mailer.RenderHTMLTo(&writes, email, "en"); err != nil {
+		t.Fatalf("RenderHTMLTo failed: %v", err)
+	}
+
+	// One write per full buffer and one for the rest
+	if max := writeCounter(len(html)/4096 + 1); writes > max {
+		t.Errorf("RenderHTMLTo should buffer its output, got %d writes", writes)
+	}
+
+	text, err := mailer.GeneratePlainText(email, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	var textBuf strings.Builder
+	if err := mailer.RenderTextTo(&textBuf, email, "en"); err != nil {
+		t.Fatalf("RenderTextTo failed: %v", err)
+	}
+
+	if textBuf.String() != text {
+		t.Error("RenderTextTo should write the same text as GeneratePlainText")
+	}
+}
+
+// writeCounter counts the writes it receives
+type writeCounter int
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	*w++
+	return len(p), nil
+}
+
+// benchmarkEmail returns an email with a large table, like a big order confirmation
+func benchmarkEmail() Email {
+	rows := make([]TableRow, 500)
+	for i := range rows {
+		rows[i] = TableRow{Cells: []string{"Wireless Headphones", "1", "$79.99"}}
+	}
+
+	return Email{
+		Body: Body{
+			Name:   "John Doe",
+			Title:  "Your order has shipped",
+			Intros: []string{"Thank you for your order."},
+			Table: Table{
+				Header: []string{"Product", "Quantity", "Price"},
+				Rows:   rows,
+			},
+		},
+	}
+}
+
+func BenchmarkGenerateHTML(b *testing.B) {
+	mailer := New(Product{Name: "Acme Corporation", Link: "https://acme.com"}, DefaultTheme)
+	email := benchmarkEmail()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := mailer.GenerateHTML(email, "en"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderHTMLTo(b *testing.B) {
+	mailer := New(Product{Name: "Acme Corporation", Link: "https://acme.com"}, DefaultTheme)
+	email := benchmarkEmail()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := mailer.RenderHTMLTo(io.Discard, email, "en"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGeneratePlainText(b *testing.B) {
+	mailer := New(Product{Name: "Acme Corporation", Link: "https://acme.com"}, DefaultTheme)
+	email := benchmarkEmail()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := mailer.GeneratePlainText(email, "en"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderTextTo(b *testing.B) {
+	mailer := New(Product{Name: "Acme Corporation", Link: "https://acme.com"}, DefaultTheme)
+	email := benchmarkEmail()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := mailer.RenderTextTo(io.Discard, email, "en"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAddMessages(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Name: "Hans",
+		},
+	}
+
+	// Render once so the localizer for "de" is cached
+	if _, err := mailer.GenerateHTML(email, "de"); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	err := mailer.AddMessages("de", map[string]string{
+		"greeting": "Hallo",
+	})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	text, err := mailer.GeneratePlainText(email, "de")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	if !strings.Contains(text, "Hallo Hans") {
+		t.Errorf("Plain text should use messages added at runtime, got:\n%s", text)
+	}
+
+	if err := mailer.AddMessages("not a tag!", map[string]string{"greeting": "x"}); err == nil {
+		t.Error("AddMessages should fail for an invalid language tag")
+	}
+}
+
+func TestConcurrentRenderAndLoad(t *testing.T) {
+	product := Product{
+		Name:      "Test Product",
+		Link:      "https://example.com",
+		Copyright: "product.copyright",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Name:     "Test User",
+			Greeting: "greeting",
+			Intros:   []string{"email.welcome.intro"},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			langs := []string{"en", "zh", "de", "es"}
+			for j := 0; j < 50; j++ {
+				lang := langs[(i+j)%len(langs)]
+				if _, err := mailer.GenerateHTML(email, lang); err != nil {
+					t.Errorf("GenerateHTML failed: %v", err)
+				}
+				if _, err := mailer.GeneratePlainText(email, lang); err != nil {
+					t.Errorf("GeneratePlainText failed: %v", err)
+				}
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 20; j++ {
+			if err := mailer.LoadMessageFileFS(testFS, "testdata/en.json"); err != nil {
+				t.Errorf("LoadMessageFileFS failed: %v", err)
+			}
+			if err := mailer.LoadMessageFileFS(testFS, "testdata/zh.json"); err != nil {
+				t.Errorf("LoadMessageFileFS failed: %v", err)
+			}
+			if err := mailer.AddMessages("de", map[string]string{"greeting": "Hallo"}); err != nil {
+				t.Errorf("AddMessages failed: %v", err)
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	html, err := mailer.GenerateHTML(email, "zh")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	if !strings.Contains(html, "您好") {
+		t.Error("Chinese HTML should contain '您好' after concurrent loading")
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	mailer := New(Product{Name: "Test Product"}, DefaultTheme)
+
+	if langs := mailer.Languages(); len(langs) != 1 || langs[0] != "en" {
+		t.Errorf("Expected only the default language, got %v", langs)
+	}
+
+	if err := mailer.LoadMessageFile("testdata/zh.json"); err != nil {
+		t.Fatalf("Failed to load message file: %v", err)
+	}
+
+	if langs := mailer.Languages(); len(langs) != 2 || langs[1] != "zh" {
+		t.Errorf("Languages should include loaded languages, got %v", langs)
+	}
+}
+
+func TestLocalizerCache(t *testing.T) {
+	mailer := New(Product{Name: "Test Product"}, DefaultTheme)
+	if err := mailer.LoadMessageFile("testdata/zh.json"); err != nil {
+		t.Fatalf("Failed to load message file: %v", err)
+	}
+
+	// Spellings of the same languages share a localizer
+	for _, lang := range []string{"zh-cn", "zh-CN", "ZH-cn"} {
+		mailer.Translate("greeting", lang, nil)
+	}
+	mailer.Translate("greeting", "fr-ch, fr;q=0.9", nil)
+	mailer.Translate("greeting", "fr-CH,fr", nil)
+	if n := mailer.localizers.len(); n != 2 {
+		t.Errorf("Expected 2 cached localizers, got %d", n)
+	}
+
+	// Many distinct languages evict the least recently used localizers
+	for i := range maxCachedLocalizers * 2 {
+		mailer.Translate("greeting", fmt.Sprintf("en-x-lang%d", i), nil)
+		mailer.Translate("greeting", "zh-CN", nil)
+	}
+	if n := mailer.localizers.len(); n != maxCachedLocalizers {
+		t.Errorf("Expected %d cached localizers, got %d", maxCachedLocalizers, n)
+	}
+	if _, ok := mailer.localizers.get("zh-CN"); !ok {
+		t.Error("Recently used localizer should stay cached")
+	}
+	if got := mailer.Translate("greeting", "zh-cn", nil); got != "您好" {
+		t.Errorf("Expected Chinese greeting, got %q", got)
+	}
+}
+
+func TestLayouts(t *testing.T) {
+	product := Product{Name: "Test Product", Link: "https://example.com"}
+
+	mailer := New(product, DefaultTheme,
+		options.WithLayoutString("newsletter", `<div class="newsletter">{{.Body.Greeting}} {{.Body.Name}}</div>`),
+		options.WithLayout("receipt", template.Must(template.New("receipt").Parse(`<div class="receipt">{{.Product.Name}}</div>`))),
+	)
+	if err := mailer.LoadMessageFile("testdata/en.json"); err != nil {
+		t.Fatalf("Failed to load message file: %v", err)
+	}
+
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{"newsletter", `<div class="newsletter">Hello Jane</div>`},
+		{"receipt", `<div class="receipt">Test Product</div>`},
+		{"", "<!DOCTYPE html>"},
+		{"missing", "<!DOCTYPE html>"},
+	}
+
+	for _, tt := range tests {
+		html, err := mailer.GenerateHTML(Email{Layout: tt.layout, Body: Body{Name: "Jane"}}, "en")
+		if err != nil {
+			t.Fatalf("GenerateHTML with layout %q failed: %v", tt.layout, err)
+		}
+		if !strings.HasPrefix(strings.TrimSpace(html), tt.want) {
+			t.Errorf("Layout %q should render %q, got %q", tt.layout, tt.want, html[:min(len(html), 60)])
+		}
+	}
+
+	if !mailer.HasLayout("newsletter") || mailer.HasLayout("missing") {
+		t.Error("HasLayout should report registered layouts")
+	}
+
+	err := mailer.Validate(Email{Layout: "missing"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Field != "Layout" {
+		t.Errorf("Validate should reject unknown layouts, got %v", err)
+	}
+}
+
+func TestTemplateBlocks(t *testing.T) {
+	product := Product{Name: "Test Product", Link: "https://example.com", Copyright: "© Test"}
+
+	mailer := New(product, DefaultTheme,
+		options.WithTemplateBlock("footer", `<div class="custom-footer">{{.Product.Copyright}} · Unsubscribe</div>`),
+		options.WithTemplateBlock("greeting", `<p class="custom-greeting">Hi {{.Body.Name}}!</p>`),
+	)
+
+	html, err := mailer.GenerateHTML(Email{Body: Body{Name: "Jane", Intros: []string{"Welcome aboard."}}}, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	for _, s := range []string{`<div class="custom-footer">© Test · Unsubscribe</div>`, `<p class="custom-greeting">Hi Jane!</p>`, "Welcome aboard.", `class="email-body"`} {
+		if !strings.Contains(html, s) {
+			t.Errorf("HTML should contain %q", s)
+		}
+	}
+	if strings.Contains(html, `class="email-footer"`) || strings.Contains(html, `class="email-greeting"`) {
+		t.Error("Overridden blocks should not render their default content")
+	}
+
+	// The overrides survive a template reload
+	dir := t.TempDir()
+	path := dir + "/email.html"
+	if err := os.WriteFile(path, []byte(`<main>{{block "footer" .}}default{{end}}</main>{{block "greeting" .}}{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mailer.Reload("", path); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	html, _ = mailer.GenerateHTML(Email{Body: Body{Name: "Jane"}}, "en")
+	if !strings.Contains(html, `<main><div class="custom-footer">`) {
+		t.Errorf("Reloaded template should keep the block overrides, got %q", html)
+	}
+}
+
+func TestTemplateBlocksKeepCustomTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("email").Parse(`{{block "footer" .}}original{{end}}`))
+	New(Product{}, DefaultTheme, options.WithCustomTemplate(tmpl), options.WithTemplateBlock("footer", "override"))
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, nil); err != nil || buf.String() != "original" {
+		t.Errorf("Block overrides should not modify the provided template, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestTemplateBlockUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Overriding an undefined block should panic")
+		}
+	}()
+	New(Product{}, DefaultTheme, options.WithTemplateBlock("sidebar", "<aside></aside>"))
+}
+
+func TestPlainTextTemplate(t *testing.T) {
+	product := Product{Name: "Acme", Link: "https://acme.com"}
+	email := Email{
+		Body: Body{
+			Name:        "Jane",
+			Table:       Table{Header: []string{"Item", "Qty"}, Rows: []TableRow{{Cells: []string{"Widget", "2"}}}},
+			Attachments: []Attachment{{Name: "invoice.pdf", URL: "https://acme.com/invoice.pdf"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opt  options.Option
+	}{
+		{"string", options.WithPlainTextTemplateString("{{.Body.Greeting}} {{.Body.Name}} from {{.Product.Name}}\n{{textTable .Body.Table}}{{range .Body.Attachments}}Files: {{.Name}}{{end}}")},
+		{"file", options.WithPlainTextTemplateFile(fstest.MapFS{
+			"email.txt": {Data: []byte("{{.Body.Greeting}} {{.Body.Name}} from {{.Product.Name}}\n{{textTable .Body.Table}}{{range .Body.Attachments}}Files: {{.Name}}{{end}}")},
+		}, "email.txt")},
+		{"parsed", options.WithPlainTextTemplate(texttemplate.Must(texttemplate.New("email").Funcs(PlainTextTemplateFuncs()).Parse(
+			"{{t \"greeting\"}} {{.Body.Name}} from {{.Product.Name}}\n{{textTable .Body.Table}}{{range .Body.Attachments}}Files: {{.Name}}{{end}}")))},
+	}
+
+	want := "Hello Jane from Acme\nItem   | Qty\n-------+----\nWidget | 2\nFiles: invoice.pdf"
+
+	for _, tt := range tests {
+		mailer := New(product, DefaultTheme, tt.opt)
+		if err := mailer.LoadMessageFile("testdata/en.json"); err != nil {
+			t.Fatalf("Failed to load message file: %v", err)
+		}
+
+		text, err := mailer.GeneratePlainText(email, "en")
+		if err != nil {
+			t.Fatalf("%s: GeneratePlainText failed: %v", tt.name, err)
+		}
+		if text != want {
+			t.Errorf("%s: expected %q, got %q", tt.name, want, text)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package mailingo
+
+import (
+	"slices"
+	"sort"
+)
+
+// MessageIDs returns the sorted message IDs that rendering the email looks up:
+// the translatable fields of the body, and the built-in messages used as defaults,
+// i.e. MessageGreeting and MessageSignature when the body does not set them,
+// MessageCopyright when the product has no copyright, and MessageAttachments
+// when the body has attachments. When Body.Formality or Body.Gender is set, the
+// IDs of the variants they select (e.g., "greeting.formal") follow each message ID.
+//
+// Plain text that is not a message ID (e.g., an intro written in English) is
+// returned as well, since it is looked up like any other message ID. Messages
+// used by custom templates through the t and tn functions are not included.
+//
+// Example:
+//
+//	for _, id := range mailer.MessageIDs(email) {
+//	    if mailer.Translate(id, "de", nil) == id {
+//	        log.Printf("missing German translation for %q", id)
+//	    }
+//	}
+func (m *Mailer) MessageIDs(email Email) []string {
+	suffixes := variantSuffixes(email.Body.Formality, email.Body.Gender)
+	neutral := m.neutralMessageIDs(email)
+	ids := make([]string, 0, len(neutral)*(len(suffixes)+1))
+	for _, id := range neutral {
+		ids = append(ids, id)
+		for _, suffix := range suffixes {
+			ids = append(ids, id+suffix)
+		}
+	}
+	sort.Strings(ids)
+	return slices.Compact(ids)
+}
+
+// neutralMessageIDs returns the sorted message IDs of the email without variants
+func (m *Mailer) neutralMessageIDs(email Email) []string {
+	body := email.Body
+	seen := make(map[string]bool)
+	add := func(key, defaultKey string) {
+		if key == "" {
+			key = defaultKey
+		}
+		if key != "" {
+			seen[key] = true
+		}
+	}
+
+	add(body.Greeting, MessageGreeting)
+	add(body.Signature, MessageSignature)
+	add(body.Title, "")
+	add(m.product.Copyright, MessageCopyright)
+	for _, intro := range body.Intros {
+		add(intro, "")
+	}
+	for _, outro := range body.Outros {
+		add(outro, "")
+	}
+	for _, entry := range body.Dictionary {
+		add(entry.Key, "")
+	}
+	for _, action := range body.Actions {
+		add(action.Instructions, "")
+		add(action.Button.Text, "")
+	}
+	tableMessageIDs(body.Table, add)
+	if len(body.Attachments) > 0 {
+		add(MessageAttachments, "")
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// tableMessageIDs adds the message IDs translated by processTable
+func tableMessageIDs(table Table, add func(key, defaultKey string)) {
+	if len(table.Data) > 0 {
+		for _, row := range table.Data {
+			for _, cell := range row {
+				add(cell.Key, "")
+			}
+		}
+		return
+	}
+
+	hasRowKeys := false
+	for _, row := range table.Rows {
+		if row.Key != "" {
+			hasRowKeys = true
+			add(row.Key, "")
+		}
+	}
+	if hasRowKeys {
+		add(table.KeyHeader, "")
+	}
+	for _, header := range table.Header {
+		add(header, "")
+	}
+}
@@ -0,0 +1,91 @@
+package mailingo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMessageIDs(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Title:      "email.welcome.title",
+			Intros:     []string{"email.welcome.intro", "email.welcome.intro"},
+			Dictionary: []Entry{{Key: "email.account.username", Value: "jane"}},
+			Table: Table{
+				KeyHeader: "email.table.item",
+				Header:    []string{"email.table.price"},
+				Rows:      []TableRow{{Key: "email.table.plan", Cells: []string{"$10"}}},
+			},
+			Actions: []Action{{
+				Instructions: "email.action.instructions",
+				Button:       Button{Text: "email.action.button", Link: "https://acme.com"},
+			}},
+			Outros:      []string{"email.welcome.outro"},
+			Attachments: []Attachment{{Name: "invoice.pdf", URL: "https://acme.com/invoice.pdf"}},
+		},
+	}
+
+	want := []string{
+		"email.account.username",
+		"email.action.button",
+		"email.action.instructions",
+		"email.table.item",
+		"email.table.plan",
+		"email.table.price",
+		"email.welcome.intro",
+		"email.welcome.outro",
+		"email.welcome.title",
+		MessageGreeting,
+		MessageAttachments,
+		MessageCopyright,
+		MessageSignature,
+	}
+	slices.Sort(want)
+
+	if got := mailer.MessageIDs(email); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// The variants selected by the recipient attributes follow each message ID
+	email.Body.Formality, email.Body.Gender = FormalityFormal, GenderFeminine
+	var variants []string
+	for _, id := range want {
+		variants = append(variants, id, id+".formal.feminine", id+".formal", id+".feminine")
+	}
+	slices.Sort(variants)
+
+	if got := mailer.MessageIDs(email); !slices.Equal(got, variants) {
+		t.Errorf("Expected %v, got %v", variants, got)
+	}
+}
+
+func TestMessageIDsOverrideDefaults(t *testing.T) {
+	mailer := New(Product{Name: "Acme", Copyright: "acme.copyright"}, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Greeting:  "email.greeting.formal",
+			Signature: "email.signature.team",
+			Formality: FormalityFormal,
+			Table: Table{
+				Data: [][]Entry{
+					{{Key: "email.table.item"}, {Value: "Price"}},
+					{{Key: "email.table.plan"}, {Value: "$10"}},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"acme.copyright", "acme.copyright.formal",
+		"email.greeting.formal", "email.greeting.formal.formal",
+		"email.signature.team", "email.signature.team.formal",
+		"email.table.item", "email.table.item.formal",
+		"email.table.plan", "email.table.plan.formal",
+	}
+	if got := mailer.MessageIDs(email); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
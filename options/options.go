@@ -4,6 +4,7 @@ package options
 import (
 	"html/template"
 	"io/fs"
+	texttemplate "text/template"
 )
 
 // Option is a function that configures a Mailer.
@@ -16,6 +17,123 @@ type Config struct {
 	CustomTemplateFS   fs.FS
 	CustomTemplatePath string
 	CustomCSS          string
+	TemplateBlocks     map[string]string
+	FuncMap            template.FuncMap
+
+	PlainTextTemplate     *texttemplate.Template
+	PlainTextTemplateText string
+	PlainTextTemplateFS   fs.FS
+	PlainTextTemplatePath string
+
+	Layouts   map[string]Layout
+	Fallbacks map[string][]string
+
+	TrackingPixelURL            string
+	TrackingSuppressedLanguages []string
+	TrackingSuppressor          func(lang, trackingID string) bool
+
+	Validate           bool
+	ValidateLinks      bool
+	AllowInsecureLinks bool
+	AllowRelativeLinks bool
+	AllowMailtoLinks   bool
+
+	HTMLSizeWarn    int
+	HTMLSizeMax     int
+	MessageSizeWarn int
+	MessageSizeMax  int
+}
+
+// WithPlainTextTemplate allows you to provide your own plain text template.
+// The template receives the same data as the HTML template, without TrackingPixel,
+// and can use the textTable function to format .Body.Table with aligned columns.
+//
+// Example:
+//
+//	tmpl := texttemplate.Must(texttemplate.New("email").Parse(`{{.Body.Greeting}} {{.Body.Name}}...`))
+//	mailer := mailingo.New(product, theme, options.WithPlainTextTemplate(tmpl))
+func WithPlainTextTemplate(tmpl *texttemplate.Template) Option {
+	return func(c *Config) {
+		c.PlainTextTemplate = tmpl
+	}
+}
+
+// WithPlainTextTemplateString allows you to provide your own plain text template as a string.
+// The template string will be parsed when creating the Mailer.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithPlainTextTemplateString("{{.Body.Greeting}} {{.Body.Name}},\n\n{{range .Body.Intros}}{{.}}\n{{end}}"))
+func WithPlainTextTemplateString(tmplStr string) Option {
+	return func(c *Config) {
+		c.PlainTextTemplateText = tmplStr
+	}
+}
+
+// WithPlainTextTemplateFile allows you to load a plain text template from a filesystem,
+// e.g. embedded with go:embed.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithPlainTextTemplateFile(templatesFS, "templates/mytemplate.txt"))
+func WithPlainTextTemplateFile(filesystem fs.FS, path string) Option {
+	return func(c *Config) {
+		c.PlainTextTemplateFS = filesystem
+		c.PlainTextTemplatePath = path
+	}
+}
+
+// WithTemplateBlock replaces a single named block of the template while inheriting the rest,
+// e.g. to change the footer without copying the whole default template.
+// The text is the new block content; it receives the same data as the whole template.
+// Overriding a block that the template does not define panics when creating the Mailer.
+//
+// The default template defines the blocks "header", "greeting", "title", "intros",
+// "dictionary", "table", "actions", "outros", "attachments", "signature" and "footer".
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithTemplateBlock("footer", `<div class="email-footer">{{.Product.Copyright}} · <a href="https://example.com/unsubscribe">Unsubscribe</a></div>`))
+func WithTemplateBlock(name, text string) Option {
+	return func(c *Config) {
+		if c.TemplateBlocks == nil {
+			c.TemplateBlocks = make(map[string]string)
+		}
+		c.TemplateBlocks[name] = text
+	}
+}
+
+// WithFuncMap registers functions for use in templates given as strings or files, in
+// addition to the built-in functions t, tn, formatMoney and formatDate (see mailingo.TemplateFuncs).
+// Functions with the name of a built-in function replace it. Calling WithFuncMap
+// several times merges the maps.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithFuncMap(template.FuncMap{"upper": strings.ToUpper}),
+//	    options.WithCustomTemplateString(`<h1>{{upper .Product.Name}}</h1>`))
+func WithFuncMap(funcs template.FuncMap) Option {
+	return func(c *Config) {
+		if c.FuncMap == nil {
+			c.FuncMap = make(template.FuncMap, len(funcs))
+		}
+		for name, fn := range funcs {
+			c.FuncMap[name] = fn
+		}
+	}
+}
+
+// Layout is the source of a named layout registered with WithLayout,
+// WithLayoutString or WithLayoutFile. Exactly one source is set.
+type Layout struct {
+	Template *template.Template
+	Text     string
+	FS       fs.FS
+	Path     string
 }
 
 // WithCustomTemplate allows you to provide your own HTML template.
@@ -76,3 +194,205 @@ func WithCustomCSS(css string) Option {
 		c.CustomCSS = css
 	}
 }
+
+// WithLayout registers a named layout that emails select with Email.Layout.
+// Emails without a layout use the default template. Layouts use the same data
+// structure as the default template, and registering a name twice replaces the layout.
+//
+// Example:
+//
+//	newsletter := template.Must(template.New("newsletter").Parse(`<html>...</html>`))
+//	mailer := mailingo.New(product, theme, options.WithLayout("newsletter", newsletter))
+//
+//	email := mailingo.Email{Layout: "newsletter", Body: body}
+func WithLayout(name string, tmpl *template.Template) Option {
+	return func(c *Config) {
+		c.addLayout(name, Layout{Template: tmpl})
+	}
+}
+
+// WithLayoutString registers a named layout from a template string.
+// The template string will be parsed when creating the Mailer.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithLayoutString("newsletter", `<!DOCTYPE html><html>...</html>`))
+func WithLayoutString(name, tmplStr string) Option {
+	return func(c *Config) {
+		c.addLayout(name, Layout{Text: tmplStr})
+	}
+}
+
+// WithLayoutFile registers a named layout loaded from a filesystem, e.g. embedded with go:embed.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithLayoutFile("newsletter", templatesFS, "templates/newsletter.html"))
+func WithLayoutFile(name string, filesystem fs.FS, path string) Option {
+	return func(c *Config) {
+		c.addLayout(name, Layout{FS: filesystem, Path: path})
+	}
+}
+
+// addLayout registers a named layout
+func (c *Config) addLayout(name string, layout Layout) {
+	if c.Layouts == nil {
+		c.Layouts = make(map[string]Layout)
+	}
+	c.Layouts[name] = layout
+}
+
+// WithFallback sets the languages whose messages are used, in order, when a message is
+// missing in lang. Each language of the chain is looked up exactly: "es-AR" falls back
+// to a loaded "es" catalog, never to a loaded "es-MX". Chains are followed transitively
+// and always end in the default language English. Languages without a fallback chain
+// use the closest loaded language only, and its missing messages fall back to the
+// built-in translations or the message ID.
+//
+// This also layers catalogs, e.g. a tenant's overrides loaded under a private use tag
+// like "en-x-acme" fall back to the shared "en" catalog.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithFallback("es-AR", "es"),
+//	    options.WithFallback("en-x-acme", "en"))
+func WithFallback(lang string, fallbacks ...string) Option {
+	return func(c *Config) {
+		if c.Fallbacks == nil {
+			c.Fallbacks = make(map[string][]string)
+		}
+		c.Fallbacks[lang] = fallbacks
+	}
+}
+
+// WithTrackingPixel enables a per-message open tracking pixel in the generated HTML.
+// The pixel URL is built from baseURL with the email's TrackingID appended as the
+// "id" query parameter. Emails without a TrackingID never receive a pixel, and the
+// pixel is never added to plain text output.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithTrackingPixel("https://track.example.com/open.gif"))
+func WithTrackingPixel(baseURL string) Option {
+	return func(c *Config) {
+		c.TrackingPixelURL = baseURL
+	}
+}
+
+// WithTrackingSuppressedLanguages disables the tracking pixel for the given languages or regions.
+// A bare language (e.g., "de") suppresses every region of that language, while a tag with a
+// region (e.g., "fr-FR") only suppresses that region. This is useful for GDPR-sensitive markets.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithTrackingPixel("https://track.example.com/open.gif"),
+//	    options.WithTrackingSuppressedLanguages("de", "fr-FR"))
+func WithTrackingSuppressedLanguages(langs ...string) Option {
+	return func(c *Config) {
+		c.TrackingSuppressedLanguages = append(c.TrackingSuppressedLanguages, langs...)
+	}
+}
+
+// WithTrackingSuppressor registers a function deciding per message whether the tracking pixel
+// must be omitted. It receives the render language and the email's TrackingID and should
+// return true to suppress tracking, e.g. for recipients who have not given consent.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithTrackingPixel("https://track.example.com/open.gif"),
+//	    options.WithTrackingSuppressor(func(lang, trackingID string) bool {
+//	        return !consent.HasOptedIn(trackingID)
+//	    }))
+func WithTrackingSuppressor(suppress func(lang, trackingID string) bool) Option {
+	return func(c *Config) {
+		c.TrackingSuppressor = suppress
+	}
+}
+
+// WithValidation makes GenerateHTML and GeneratePlainText validate every email before rendering.
+// Structural problems (actions without links, attachments without URLs, table rows that do not
+// match the header row, ...) and links rejected by the link policy make rendering fail with
+// an error listing each offending field.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme, options.WithValidation())
+func WithValidation() Option {
+	return func(c *Config) {
+		c.Validate = true
+	}
+}
+
+// WithLinkValidation makes GenerateHTML and GeneratePlainText validate every Button.Link and
+// Attachment.URL before rendering. Instead of html/template silently neutralizing unsafe links
+// to "#ZgotmplZ", rendering fails with an error naming each offending field. By default only absolute
+// HTTPS URLs are accepted; use WithInsecureLinks, WithRelativeLinks and WithMailtoLinks
+// to relax the policy.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme, options.WithLinkValidation())
+func WithLinkValidation() Option {
+	return func(c *Config) {
+		c.ValidateLinks = true
+	}
+}
+
+// WithInsecureLinks allows plain HTTP links to pass link validation.
+func WithInsecureLinks() Option {
+	return func(c *Config) {
+		c.AllowInsecureLinks = true
+	}
+}
+
+// WithRelativeLinks allows relative links (e.g., "/account") to pass link validation.
+func WithRelativeLinks() Option {
+	return func(c *Config) {
+		c.AllowRelativeLinks = true
+	}
+}
+
+// WithMailtoLinks allows mailto: links to pass link validation.
+func WithMailtoLinks() Option {
+	return func(c *Config) {
+		c.AllowMailtoLinks = true
+	}
+}
+
+// WithHTMLSizeLimits sets the HTML size thresholds in bytes used by Mailer.AnalyzeSize.
+// Exceeding warn adds a warning to the report, exceeding max returns an error.
+// A negative value disables the threshold. By default a warning is reported
+// above 102KB, where Gmail clips messages.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithHTMLSizeLimits(90*1024, mailingo.GmailClipSize))
+func WithHTMLSizeLimits(warn, max int) Option {
+	return func(c *Config) {
+		c.HTMLSizeWarn = warn
+		c.HTMLSizeMax = max
+	}
+}
+
+// WithMessageSizeLimits sets the thresholds in bytes for the estimated size of the complete
+// message, including encoded SMTP attachments, used by Mailer.AnalyzeSize.
+// Exceeding warn adds a warning to the report, exceeding max returns an error.
+// A zero or negative value disables the threshold.
+//
+// Example:
+//
+//	mailer := mailingo.New(product, theme,
+//	    options.WithMessageSizeLimits(10<<20, 25<<20))
+func WithMessageSizeLimits(warn, max int) Option {
+	return func(c *Config) {
+		c.MessageSizeWarn = warn
+		c.MessageSizeMax = max
+	}
+}
@@ -0,0 +1,77 @@
+package preview
+
+import "html/template"
+
+// indexTemplate is the preview page: a sidebar with the samples and switchers,
+// and an iframe with the rendered sample. The page polls /version and reloads
+// itself when the messages or the template were reloaded.
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>{{with .Product.Name}}{{.}} – {{end}}mailingo preview</title>
+  <style>
+    body { margin: 0; display: flex; height: 100vh; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; font-size: 14px; color: #333; }
+    nav { width: 260px; flex-shrink: 0; box-sizing: border-box; padding: 16px; background: #F2F4F6; border-right: 1px solid #E2E6EA; overflow-y: auto; }
+    nav h1 { margin: 0 0 16px; font-size: 16px; }
+    nav h2 { margin: 20px 0 8px; font-size: 12px; text-transform: uppercase; color: #74787E; }
+    nav ul { margin: 0; padding: 0; list-style: none; }
+    nav li a { display: block; padding: 6px 8px; border-radius: 4px; color: #333; text-decoration: none; }
+    nav li a:hover { background: #E2E6EA; }
+    nav li a.active { background: #3869D4; color: #FFF; }
+    nav label { display: block; margin-bottom: 10px; }
+    nav select { display: block; width: 100%; margin-top: 4px; }
+    main { flex: 1; }
+    iframe { width: 100%; height: 100%; border: 0; background: #FFF; }
+  </style>
+</head>
+<body>
+  <nav>
+    <h1>{{with .Product.Name}}{{.}}{{else}}mailingo{{end}} preview</h1>
+    <form method="get" action="/">
+      <input type="hidden" name="sample" value="{{.Selection.Sample.Name}}">
+      <label>Language
+        <select name="lang" onchange="this.form.submit()">
+          {{range .Languages}}<option value="{{.}}"{{if eq . $.Selection.Lang}} selected{{end}}>{{.}}</option>{{end}}
+        </select>
+      </label>
+      <label>Theme
+        <select name="theme" onchange="this.form.submit()">
+          {{range .Themes}}<option value="{{.}}"{{if eq . $.Selection.Theme}} selected{{end}}>{{.}}</option>{{end}}
+        </select>
+      </label>
+      <label>View
+        <select name="view" onchange="this.form.submit()">
+          <option value="html"{{if eq .Selection.View "html"}} selected{{end}}>HTML</option>
+          <option value="text"{{if eq .Selection.View "text"}} selected{{end}}>Plain text</option>
+        </select>
+      </label>
+      <noscript><button type="submit">Apply</button></noscript>
+    </form>
+    <h2>Samples</h2>
+    <ul>
+      {{range .Samples}}<li><a href="/?sample={{.Name}}&amp;lang={{$.Selection.Lang}}&amp;theme={{$.Selection.Theme}}&amp;view={{$.Selection.View}}"{{if eq .Name $.Selection.Sample.Name}} class="active"{{end}}>{{.Name}}</a></li>
+      {{end}}
+    </ul>
+  </nav>
+  <main>
+    <iframe title="Rendered email" src="/render?sample={{.Selection.Sample.Name}}&amp;lang={{.Selection.Lang}}&amp;theme={{.Selection.Theme}}&amp;view={{.Selection.View}}"></iframe>
+  </main>
+  <script>
+    (function () {
+      var version = {{.Version}};
+      setInterval(function () {
+        fetch("/version", { cache: "no-store" })
+          .then(function (response) { return response.text(); })
+          .then(function (current) {
+            if (Number(current) !== version) {
+              location.reload();
+            }
+          })
+          .catch(function () {});
+      }, 1000);
+    })();
+  </script>
+</body>
+</html>
+`))
@@ -0,0 +1,275 @@
+// Package preview serves rendered mailingo emails over HTTP for local development.
+//
+// The preview page lists the registered sample emails and lets you switch the
+// language, including the pseudo-locales, the theme and between the HTML and
+// plain-text versions. When the
+// locale directory or the template file changes, the messages are reloaded and
+// open preview pages refresh automatically.
+//
+// Example:
+//
+//	server, err := preview.New(preview.Config{
+//	    Product:   product,
+//	    LocaleDir: "locales",
+//	    Samples: []preview.Sample{
+//	        {Name: "Welcome", Email: welcomeEmail},
+//	        {Name: "Password reset", Email: resetEmail},
+//	    },
+//	})
+//	go server.Watch(ctx, func(err error) { log.Printf("reload failed: %v", err) })
+//	log.Fatal(http.ListenAndServe("localhost:8080", server))
+package preview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib-x/mailingo"
+	"github.com/lib-x/mailingo/options"
+)
+
+// Sample is an email listed in the preview sidebar
+type Sample struct {
+	Name  string         // Name shown in the sidebar, must be unique
+	Email mailingo.Email // The email to render
+	Lang  string         // Language selected when the sample is opened without one (optional)
+}
+
+// Theme is a named theme selectable in the preview
+type Theme struct {
+	Name  string
+	Theme mailingo.Theme
+}
+
+// DefaultThemes are the themes offered when Config.Themes is empty
+var DefaultThemes = []Theme{
+	{Name: "default", Theme: mailingo.DefaultTheme},
+	{Name: "flat", Theme: mailingo.FlatTheme},
+}
+
+// Config configures the preview server
+type Config struct {
+	Product      mailingo.Product // Product rendered in all samples
+	Themes       []Theme          // Selectable themes; the first one is preselected (defaults to DefaultThemes)
+	Options      []options.Option // Options passed to mailingo.New
+	LocaleDir    string           // Directory containing the message files (optional)
+	TemplateFile string           // HTML template file replacing the default template (optional)
+	Samples      []Sample         // Sample emails listed in the sidebar
+	Interval     time.Duration    // Polling interval of Watch (defaults to mailingo.DefaultWatchInterval)
+}
+
+// Server is an http.Handler serving the preview page and the rendered samples
+type Server struct {
+	config  Config
+	mailer  *mailingo.Mailer            // Mailer loading the messages and template shared by all themes
+	themes  map[string]*mailingo.Mailer // Views of the mailer by theme name
+	version atomic.Int64                // Incremented on every reload, polled by the preview page
+}
+
+// New creates a preview server and loads the locale directory and template file.
+func New(cfg Config) (*Server, error) {
+	if len(cfg.Samples) == 0 {
+		return nil, errors.New("preview: no samples")
+	}
+	if len(cfg.Themes) == 0 {
+		cfg.Themes = DefaultThemes
+	}
+
+	names := make(map[string]bool, len(cfg.Samples))
+	for _, sample := range cfg.Samples {
+		if names[sample.Name] {
+			return nil, fmt.Errorf("preview: duplicate sample %q", sample.Name)
+		}
+		names[sample.Name] = true
+	}
+
+	s := &Server{
+		config: cfg,
+		mailer: mailingo.New(cfg.Product, cfg.Themes[0].Theme, cfg.Options...),
+		themes: make(map[string]*mailingo.Mailer, len(cfg.Themes)),
+	}
+	for _, theme := range cfg.Themes {
+		if _, ok := s.themes[theme.Name]; ok {
+			return nil, fmt.Errorf("preview: duplicate theme %q", theme.Name)
+		}
+
+		// Views share the messages and template of the mailer, so they are loaded once
+		view, err := s.mailer.Tenant(mailingo.Tenant{Theme: theme.Theme})
+		if err != nil {
+			return nil, fmt.Errorf("preview: %w", err)
+		}
+		s.themes[theme.Name] = view
+	}
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload reloads the locale directory and template file
+func (s *Server) reload() error {
+	if s.config.LocaleDir == "" && s.config.TemplateFile == "" {
+		return nil
+	}
+
+	if err := s.mailer.Reload(s.config.LocaleDir, s.config.TemplateFile); err != nil {
+		return fmt.Errorf("preview: %w", err)
+	}
+	s.version.Add(1)
+	return nil
+}
+
+// Watch reloads the locale directory and template file whenever they change,
+// so open preview pages show the latest messages. Watch blocks until ctx is done
+// and returns ctx.Err(). Reload errors are reported to onError, if not nil.
+func (s *Server) Watch(ctx context.Context, onError func(error)) error {
+	if s.config.LocaleDir == "" && s.config.TemplateFile == "" {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return s.mailer.Watch(ctx, mailingo.WatchConfig{
+		LocaleDir:    s.config.LocaleDir,
+		TemplateFile: s.config.TemplateFile,
+		Interval:     s.config.Interval,
+		OnReload:     func() { s.version.Add(1) },
+		OnError:      onError,
+	})
+}
+
+// ServeHTTP serves the preview page at "/", the rendered sample at "/render"
+// and the reload version polled by the page at "/version".
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		s.serveIndex(w, r)
+	case "/render":
+		s.serveRender(w, r)
+	case "/version":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, strconv.FormatInt(s.version.Load(), 10))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// selection is the sample, language, theme and view selected in the query
+type selection struct {
+	Sample Sample
+	Lang   string
+	Theme  string
+	View   string // "html" or "text"
+}
+
+// selection reads the selection from the query, applying the defaults
+func (s *Server) selection(r *http.Request) (selection, bool) {
+	query := r.URL.Query()
+
+	sel := selection{
+		Sample: s.config.Samples[0],
+		Theme:  s.config.Themes[0].Name,
+		View:   "html",
+	}
+	if name := query.Get("sample"); name != "" {
+		found := false
+		for _, sample := range s.config.Samples {
+			if sample.Name == name {
+				sel.Sample, found = sample, true
+				break
+			}
+		}
+		if !found {
+			return sel, false
+		}
+	}
+	if theme := query.Get("theme"); theme != "" {
+		if _, ok := s.themes[theme]; !ok {
+			return sel, false
+		}
+		sel.Theme = theme
+	}
+	if view := query.Get("view"); view == "text" {
+		sel.View = view
+	}
+
+	sel.Lang = query.Get("lang")
+	if sel.Lang == "" {
+		sel.Lang = sel.Sample.Lang
+	}
+	if sel.Lang == "" {
+		sel.Lang = "en"
+	}
+	return sel, true
+}
+
+// serveRender renders the selected sample
+func (s *Server) serveRender(w http.ResponseWriter, r *http.Request) {
+	sel, ok := s.selection(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	mailer := s.themes[sel.Theme]
+	var (
+		body string
+		err  error
+	)
+	if sel.View == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		body, err = mailer.GeneratePlainText(sel.Sample.Email, sel.Lang)
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		body, err = mailer.GenerateHTML(sel.Sample.Email, sel.Lang)
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "Failed to render %q: %v\n", sel.Sample.Name, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprint(w, body)
+}
+
+// serveIndex serves the preview page with the sidebar and the switchers
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	sel, ok := s.selection(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	themes := make([]string, len(s.config.Themes))
+	for i, theme := range s.config.Themes {
+		themes[i] = theme.Name
+	}
+
+	languages := append(s.mailer.Languages(), mailingo.PseudoAccented, mailingo.PseudoBidi)
+	if !slices.Contains(languages, sel.Lang) {
+		languages = append(languages, sel.Lang)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	err := indexTemplate.Execute(w, map[string]interface{}{
+		"Product":   s.config.Product,
+		"Samples":   s.config.Samples,
+		"Languages": languages,
+		"Themes":    themes,
+		"Selection": sel,
+		"Version":   s.version.Load(),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,137 @@
+package preview
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib-x/mailingo"
+)
+
+func newTestServer(t *testing.T, localeDir string) *Server {
+	t.Helper()
+
+	server, err := New(Config{
+		Product:   mailingo.Product{Name: "Acme", Link: "https://acme.com"},
+		LocaleDir: localeDir,
+		Samples: []Sample{
+			{Name: "Welcome", Email: mailingo.Email{Body: mailingo.Body{Name: "Jane", Title: "email.welcome.title"}}},
+			{Name: "Receipt", Email: mailingo.Email{Body: mailingo.Body{Name: "John"}}},
+		},
+		Interval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return server
+}
+
+func get(t *testing.T, handler http.Handler, target string) (int, string, string) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	body, _ := io.ReadAll(recorder.Body)
+	return recorder.Code, recorder.Header().Get("Content-Type"), string(body)
+}
+
+func TestIndex(t *testing.T) {
+	server := newTestServer(t, "../testdata")
+
+	code, _, body := get(t, server, "/?sample=Receipt&lang=zh&theme=flat")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+
+	for _, s := range []string{
+		`<option value="zh" selected>zh</option>`,
+		`<option value="en-XA">en-XA</option>`,
+		`<option value="flat" selected>flat</option>`,
+		`class="active">Receipt</a>`,
+		`/?sample=Welcome&amp;lang=zh&amp;theme=flat&amp;view=html`,
+		`src="/render?sample=Receipt&amp;lang=zh&amp;theme=flat&amp;view=html"`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("Index should contain %q", s)
+		}
+	}
+
+	if code, _, _ := get(t, server, "/?sample=Missing"); code != http.StatusNotFound {
+		t.Errorf("Unknown sample should return 404, got %d", code)
+	}
+	if code, _, _ := get(t, server, "/?theme=missing"); code != http.StatusNotFound {
+		t.Errorf("Unknown theme should return 404, got %d", code)
+	}
+}
+
+func TestRender(t *testing.T) {
+	server := newTestServer(t, "../testdata")
+
+	code, contentType, body := get(t, server, "/render?sample=Welcome&lang=zh")
+	if code != http.StatusOK || !strings.HasPrefix(contentType, "text/html") {
+		t.Fatalf("Expected HTML, got %d %s", code, contentType)
+	}
+	if !strings.Contains(body, "您好 Jane") {
+		t.Error("HTML should be rendered in the selected language")
+	}
+
+	code, contentType, body = get(t, server, "/render?sample=Welcome&view=text")
+	if code != http.StatusOK || !strings.HasPrefix(contentType, "text/plain") {
+		t.Fatalf("Expected plain text, got %d %s", code, contentType)
+	}
+	if !strings.HasPrefix(body, "Hello Jane") {
+		t.Errorf("Plain text should start with greeting, got %q", body)
+	}
+}
+
+func TestWatchBumpsVersion(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.json")
+	if err := os.WriteFile(path, []byte(`{"greeting": "Hi"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestServer(t, dir)
+	_, _, before := get(t, server, "/version")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Watch(ctx, nil)
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{"greeting": "Howdy"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		_, _, body := get(t, server, "/render?view=text")
+		_, _, flat := get(t, server, "/render?view=text&theme=flat")
+		if _, _, after := get(t, server, "/version"); after != before && strings.HasPrefix(body, "Howdy") {
+			// All themes share the reloaded messages
+			if !strings.HasPrefix(flat, "Howdy") {
+				t.Errorf("Flat theme should render the reloaded messages, got %q", flat)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Watch should reload changed messages and bump the version")
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("New should fail without samples")
+	}
+
+	samples := []Sample{{Name: "A"}, {Name: "A"}}
+	if _, err := New(Config{Samples: samples}); err == nil {
+		t.Error("New should fail with duplicate sample names")
+	}
+}
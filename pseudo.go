@@ -0,0 +1,109 @@
+package mailingo
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// Pseudo-locales render the English messages transformed, to test layouts before
+// translations arrive. They need no message files and can be passed as lang to
+// GenerateHTML, GeneratePlainText, Translate and the other rendering methods.
+const (
+	// PseudoAccented accents all letters and expands messages by about 30% in
+	// brackets, e.g. "[Ŵéļçöɱé ţö Åçɱé! one two]", to reveal hard-coded strings,
+	// missing glyphs and layouts that break with longer translations.
+	PseudoAccented = "en-XA"
+
+	// PseudoBidi renders messages right-to-left, with the HTML direction set to "rtl",
+	// to reveal layouts that break in right-to-left languages like Arabic.
+	PseudoBidi = "ar-XB"
+)
+
+// pseudoLocale transforms resolved messages of the source language
+type pseudoLocale struct {
+	source    string // Language whose messages are transformed
+	transform func(string) string
+}
+
+// pseudoLocales are the pseudo-locales by tag
+var pseudoLocales = map[string]*pseudoLocale{
+	PseudoAccented: {source: "en", transform: accentMessage},
+	PseudoBidi:     {source: "en", transform: bidiMessage},
+}
+
+// findPseudoLocale returns the pseudo-locale for lang, or nil if lang is not a pseudo-locale
+func findPseudoLocale(lang string) *pseudoLocale {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil
+	}
+	return pseudoLocales[tag.String()]
+}
+
+// apply transforms a resolved message; a nil pseudo-locale returns it unchanged
+func (p *pseudoLocale) apply(message string) string {
+	if p == nil || message == "" {
+		return message
+	}
+	return p.transform(message)
+}
+
+// accents maps ASCII letters to accented look-alikes
+var accents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// paddingWords are appended to expand accented messages
+var paddingWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// accentMessage accents the letters of the message and pads it by about 30%
+func accentMessage(message string) string {
+	accented := strings.Map(func(r rune) rune {
+		if a, ok := accents[r]; ok {
+			return a
+		}
+		return r
+	}, message)
+
+	var b strings.Builder
+	b.WriteString("[")
+	b.WriteString(accented)
+	extra := (utf8.RuneCountInString(message)*3 + 9) / 10
+	for i := 0; extra > 0; i++ {
+		word := paddingWords[i%len(paddingWords)]
+		b.WriteString(" ")
+		b.WriteString(word)
+		extra -= len(word) + 1
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// bidiMessage wraps the message in a right-to-left override, so it is displayed
+// mirrored like right-to-left text, between right-to-left marks
+func bidiMessage(message string) string {
+	return "\u200f\u202e" + message + "\u202c\u200f"
+}
+
+// rtlLanguages are the languages written right to left
+var rtlLanguages = map[string]bool{
+	"ar": true, "ckb": true, "dv": true, "fa": true, "he": true,
+	"ps": true, "sd": true, "ug": true, "ur": true, "yi": true,
+}
+
+// direction returns the text direction of lang for the HTML dir attribute, "rtl" or "ltr".
+// PseudoBidi is right to left like Arabic.
+func direction(lang string) string {
+	base, _ := language.Make(lang).Base()
+	if rtlLanguages[base.String()] {
+		return "rtl"
+	}
+	return "ltr"
+}
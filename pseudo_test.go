@@ -0,0 +1,58 @@
+package mailingo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPseudoAccented(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+	mailer.AddMessages("en", map[string]string{"email.welcome.title": "Welcome {{.Name}}"})
+
+	title := mailer.Translate("email.welcome.title", PseudoAccented, map[string]interface{}{"Name": "Jane"})
+	if !strings.HasPrefix(title, "[Ŵéļçöɱé Ĵáñé one") || !strings.HasSuffix(title, "]") {
+		t.Errorf("Title should be accented, expanded and bracketed, got %q", title)
+	}
+	if got := mailer.Translate("email.missing", "en-xa", nil); got != "email.missing" {
+		t.Errorf("Missing messages should not be transformed, got %q", got)
+	}
+
+	html, err := mailer.GenerateHTML(Email{Body: Body{Name: "Jane", Title: "email.welcome.title"}}, PseudoAccented)
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	for _, s := range []string{"[Ĥéļļö", "[Ɓéšţ ŕéĝáŕðš", `dir="ltr"`} {
+		if !strings.Contains(html, s) {
+			t.Errorf("HTML should contain %q", s)
+		}
+	}
+}
+
+func TestPseudoBidi(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+
+	text, err := mailer.GeneratePlainText(Email{Body: Body{Name: "Jane"}}, PseudoBidi)
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+	if !strings.Contains(text, "\u200f\u202eHello\u202c\u200f") {
+		t.Errorf("Greeting should be wrapped in a right-to-left override, got %q", text)
+	}
+
+	html, err := mailer.GenerateHTML(Email{Body: Body{Name: "Jane"}}, PseudoBidi)
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if !strings.Contains(html, `<html dir="rtl">`) {
+		t.Error("HTML direction should be rtl")
+	}
+}
+
+func TestDirection(t *testing.T) {
+	tests := map[string]string{"en": "ltr", "ar": "rtl", "he-IL": "rtl", "fa": "rtl", "zh-CN": "ltr", PseudoAccented: "ltr", PseudoBidi: "rtl", "": "ltr"}
+	for lang, want := range tests {
+		if got := direction(lang); got != want {
+			t.Errorf("%q: expected %s, got %s", lang, want, got)
+		}
+	}
+}
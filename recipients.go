@@ -0,0 +1,222 @@
+package mailingo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// RecipientColumns maps the columns of a recipient data source to Recipient fields.
+// Every other column becomes a template data variable in Recipient.Data.
+type RecipientColumns struct {
+	Name       string   // Column with the recipient's name (defaults to "name")
+	Lang       string   // Column with the recipient's language tag (defaults to "lang")
+	TrackingID string   // Column with the tracking ID (optional)
+	Formality  string   // Column with the recipient's formality, e.g. "formal" (optional)
+	Gender     string   // Column with the recipient's grammatical gender, e.g. "feminine" (optional)
+	Required   []string // Data columns that must be present, e.g. variables used by the messages
+}
+
+// SkippedRow describes a row that was not loaded
+type SkippedRow struct {
+	Line   int    // Line number in the source, starting at 1
+	Reason string // Why the row was skipped
+}
+
+// LoadReport summarizes a recipient load
+type LoadReport struct {
+	Loaded  int          // Number of recipients loaded
+	Skipped []SkippedRow // Rows skipped because of bad data
+}
+
+// nameColumn returns the name column, applying the default
+func (c RecipientColumns) nameColumn() string {
+	if c.Name == "" {
+		return "name"
+	}
+	return c.Name
+}
+
+// langColumn returns the language column, applying the default
+func (c RecipientColumns) langColumn() string {
+	if c.Lang == "" {
+		return "lang"
+	}
+	return c.Lang
+}
+
+// required returns all columns that must be present
+func (c RecipientColumns) required() []string {
+	return append([]string{c.nameColumn(), c.langColumn()}, c.Required...)
+}
+
+// LoadRecipientsCSV reads recipients from CSV with a header row.
+// It fails if a required column is missing from the header or a column appears
+// twice. Rows with an invalid
+// language tag or a wrong number of fields are skipped and listed in the report.
+//
+// Example:
+//
+//	recipients, report, err := mailingo.LoadRecipientsCSV(file, mailingo.RecipientColumns{
+//	    Required: []string{"code"},
+//	})
+//	for _, row := range report.Skipped {
+//	    log.Printf("line %d skipped: %s", row.Line, row.Reason)
+//	}
+func LoadRecipientsCSV(r io.Reader, columns RecipientColumns) ([]Recipient, *LoadReport, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	// Spreadsheet exports often start with a UTF-8 byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	present := make(map[string]bool, len(header))
+	for _, column := range header {
+		if present[column] && column != "" {
+			return nil, nil, fmt.Errorf("duplicate column %q", column)
+		}
+		present[column] = true
+	}
+	for _, column := range columns.required() {
+		if !present[column] {
+			return nil, nil, fmt.Errorf("missing required column %q", column)
+		}
+	}
+
+	var recipients []Recipient
+	report := &LoadReport{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				report.Skipped = append(report.Skipped, SkippedRow{Line: parseErr.Line, Reason: fmt.Sprintf("has %d fields, header has %d", len(record), len(header))})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+
+		recipient, reason := recipientFromRow(row, columns)
+		if reason != "" {
+			report.Skipped = append(report.Skipped, SkippedRow{Line: line, Reason: reason})
+			continue
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	report.Loaded = len(recipients)
+	return recipients, report, nil
+}
+
+// LoadRecipientsJSONL reads recipients from JSON Lines, one JSON object per line.
+// Blank lines are ignored. Lines that are not valid JSON objects, lack a required
+// column or have an invalid language tag are skipped and listed in the report.
+//
+// Example:
+//
+//	recipients, report, err := mailingo.LoadRecipientsJSONL(file, mailingo.RecipientColumns{})
+func LoadRecipientsJSONL(r io.Reader, columns RecipientColumns) ([]Recipient, *LoadReport, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var recipients []Recipient
+	report := &LoadReport{}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var row map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&row); err != nil || row == nil {
+			report.Skipped = append(report.Skipped, SkippedRow{Line: line, Reason: "not a JSON object"})
+			continue
+		}
+
+		recipient, reason := recipientFromRow(row, columns)
+		if reason != "" {
+			report.Skipped = append(report.Skipped, SkippedRow{Line: line, Reason: reason})
+			continue
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read JSON Lines: %w", err)
+	}
+
+	report.Loaded = len(recipients)
+	return recipients, report, nil
+}
+
+// recipientFromRow maps a row to a recipient.
+// It returns the reason if the row has to be skipped.
+func recipientFromRow(row map[string]interface{}, columns RecipientColumns) (Recipient, string) {
+	for _, column := range columns.required() {
+		if _, ok := row[column]; !ok {
+			return Recipient{}, fmt.Sprintf("missing column %q", column)
+		}
+	}
+
+	name, ok := row[columns.nameColumn()].(string)
+	if !ok {
+		return Recipient{}, fmt.Sprintf("column %q is not a string", columns.nameColumn())
+	}
+	lang, ok := row[columns.langColumn()].(string)
+	if !ok {
+		return Recipient{}, fmt.Sprintf("column %q is not a string", columns.langColumn())
+	}
+	if _, err := language.Parse(lang); err != nil {
+		return Recipient{}, fmt.Sprintf("invalid language tag %q", lang)
+	}
+
+	recipient := Recipient{
+		Name: name,
+		Lang: lang,
+		Data: make(map[string]interface{}, len(row)),
+	}
+	for column, value := range row {
+		// Unset column options are empty and must not match empty header cells
+		if column == "" {
+			continue
+		}
+
+		switch column {
+		case columns.nameColumn(), columns.langColumn():
+		case columns.TrackingID:
+			recipient.TrackingID = fmt.Sprint(value)
+		case columns.Formality:
+			recipient.Formality = fmt.Sprint(value)
+		case columns.Gender:
+			recipient.Gender = fmt.Sprint(value)
+		default:
+			recipient.Data[column] = value
+		}
+	}
+	return recipient, ""
+}
@@ -0,0 +1,177 @@
+package mailingo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoadRecipientsCSV(t *testing.T) {
+	input := `name,lang,code,tracking
+Alice,en,ALICE10,t-1
+Zhang San,zh-CN,ZHANG20,t-2
+Bob,not a tag!,BOB30,t-3
+Carol,es
+Dave,de,DAVE40,t-4
+`
+
+	recipients, report, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{
+		TrackingID: "tracking",
+		Required:   []string{"code"},
+	})
+	if err != nil {
+		t.Fatalf("LoadRecipientsCSV failed: %v", err)
+	}
+
+	if len(recipients) != 3 || report.Loaded != 3 {
+		t.Fatalf("Expected 3 recipients, got %d (report %d)", len(recipients), report.Loaded)
+	}
+
+	if recipients[1].Name != "Zhang San" || recipients[1].Lang != "zh-CN" {
+		t.Errorf("Unexpected recipient %+v", recipients[1])
+	}
+
+	if recipients[1].Data["code"] != "ZHANG20" {
+		t.Errorf("Expected code ZHANG20, got %v", recipients[1].Data["code"])
+	}
+
+	if recipients[1].TrackingID != "t-2" {
+		t.Errorf("Expected tracking ID t-2, got %s", recipients[1].TrackingID)
+	}
+
+	if _, ok := recipients[0].Data["name"]; ok {
+		t.Error("Mapped columns should not be included in Data")
+	}
+
+	if len(report.Skipped) != 2 {
+		t.Fatalf("Expected 2 skipped rows, got %+v", report.Skipped)
+	}
+
+	if report.Skipped[0].Line != 4 || !strings.Contains(report.Skipped[0].Reason, "invalid language tag") {
+		t.Errorf("Unexpected skipped row %+v", report.Skipped[0])
+	}
+
+	if report.Skipped[1].Line != 5 {
+		t.Errorf("Unexpected skipped row %+v", report.Skipped[1])
+	}
+}
+
+func TestLoadRecipientsCSVMissingColumn(t *testing.T) {
+	input := "name,language\nAlice,en\n"
+
+	_, _, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{})
+	if err == nil || !strings.Contains(err.Error(), `missing required column "lang"`) {
+		t.Errorf("Expected missing column error, got %v", err)
+	}
+
+	// Custom column names
+	recipients, _, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{Lang: "language"})
+	if err != nil {
+		t.Fatalf("LoadRecipientsCSV failed: %v", err)
+	}
+
+	if len(recipients) != 1 || recipients[0].Lang != "en" {
+		t.Errorf("Unexpected recipients %+v", recipients)
+	}
+}
+
+func TestLoadRecipientsCSVBOM(t *testing.T) {
+	input := "\ufeffname,lang\nAlice,en\n"
+
+	recipients, _, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{})
+	if err != nil {
+		t.Fatalf("LoadRecipientsCSV failed: %v", err)
+	}
+
+	if len(recipients) != 1 || recipients[0].Name != "Alice" {
+		t.Errorf("Byte order mark should be stripped from the header, got %+v", recipients)
+	}
+}
+
+func TestLoadRecipientsCSVDuplicateColumn(t *testing.T) {
+	input := "name,lang,name\nAlice,en,Bob\n"
+
+	_, _, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{})
+	if err == nil || !strings.Contains(err.Error(), `duplicate column "name"`) {
+		t.Errorf("Expected duplicate column error, got %v", err)
+	}
+
+	// Several empty header cells are not duplicates
+	if _, _, err := LoadRecipientsCSV(strings.NewReader("name,lang,,\nAlice,en,x,y\n"), RecipientColumns{}); err != nil {
+		t.Errorf("Empty header cells should be allowed, got %v", err)
+	}
+}
+
+func TestLoadRecipientsCSVEmptyHeaderCell(t *testing.T) {
+	// The trailing comma adds a column with an empty header
+	input := "name,lang,\nAlice,en,x\n"
+
+	recipients, _, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{})
+	if err != nil {
+		t.Fatalf("LoadRecipientsCSV failed: %v", err)
+	}
+
+	if len(recipients) != 1 {
+		t.Fatalf("Expected 1 recipient, got %+v", recipients)
+	}
+
+	if recipients[0].TrackingID != "" || recipients[0].Formality != "" || recipients[0].Gender != "" {
+		t.Errorf("Empty header cell should not be mapped to unset columns, got %+v", recipients[0])
+	}
+}
+
+func TestLoadRecipientsJSONL(t *testing.T) {
+	input := `{"name": "Alice", "lang": "en", "code": "ALICE10", "amount": 42.5}
+
+{"name": "Bob", "lang": "xx-invalid-tag-!"}
+not json
+{"name": "Carol"}
+{"name": "Dave", "lang": "de", "code": "DAVE40"}
+`
+
+	recipients, report, err := LoadRecipientsJSONL(strings.NewReader(input), RecipientColumns{Required: []string{"code"}})
+	if err != nil {
+		t.Fatalf("LoadRecipientsJSONL failed: %v", err)
+	}
+
+	if len(recipients) != 2 {
+		t.Fatalf("Expected 2 recipients, got %d", len(recipients))
+	}
+
+	if recipients[0].Data["amount"] != json.Number("42.5") {
+		t.Errorf("Numbers should be preserved, got %v", recipients[0].Data["amount"])
+	}
+
+	lines := []int{3, 4, 5}
+	if len(report.Skipped) != len(lines) {
+		t.Fatalf("Expected %d skipped rows, got %+v", len(lines), report.Skipped)
+	}
+
+	for i, line := range lines {
+		if report.Skipped[i].Line != line {
+			t.Errorf("Expected skipped line %d, got %d", line, report.Skipped[i].Line)
+		}
+	}
+}
+
+func TestLoadRecipientsVariantColumns(t *testing.T) {
+	input := `name,lang,formality,gender
+Ana,es,formal,feminine
+Luis,es,,
+`
+
+	recipients, _, err := LoadRecipientsCSV(strings.NewReader(input), RecipientColumns{
+		Formality: "formality",
+		Gender:    "gender",
+	})
+	if err != nil {
+		t.Fatalf("LoadRecipientsCSV failed: %v", err)
+	}
+
+	if recipients[0].Formality != FormalityFormal || recipients[0].Gender != GenderFeminine {
+		t.Errorf("Unexpected recipient %+v", recipients[0])
+	}
+	if recipients[1].Formality != "" || len(recipients[0].Data) != 0 {
+		t.Errorf("Variant columns should not become data, got %+v", recipients)
+	}
+}
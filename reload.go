@@ -0,0 +1,178 @@
+package mailingo
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is the polling interval used by Watch when none is configured
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchConfig configures Mailer.Watch
+type WatchConfig struct {
+	LocaleDir    string        // Directory containing the message files (e.g., "locales/en.json")
+	TemplateFile string        // Optional HTML template file replacing the current template
+	Interval     time.Duration // Polling interval (defaults to DefaultWatchInterval)
+	OnReload     func()        // Called after each successful reload (optional)
+	OnError      func(error)   // Called when a reload fails; the previous messages and template stay active (optional)
+}
+
+// Reload replaces all messages with the message files in localeDir and, if templateFile
+// is not empty, the HTML template with the template in templateFile. Blocks overridden
+// with options.WithTemplateBlock are applied to the new template.
+// The new messages and template are loaded completely before they are swapped in
+// atomically, so in-flight renders are not disrupted and a failed reload leaves the
+// Mailer unchanged. Messages previously added with LoadMessageFile or AddMessages
+// are discarded.
+//
+// Only .json files are loaded; the language is taken from the file name (e.g., "zh.json").
+// If localeDir is empty, no message files are loaded.
+func (m *Mailer) Reload(localeDir, templateFile string) error {
+	bundle := newBundle()
+	catalogs := newExactCatalogs(m.fallbacks)
+
+	var files []string
+	if localeDir != "" {
+		var err error
+		if files, err = messageFiles(localeDir); err != nil {
+			return err
+		}
+	}
+	for _, path := range files {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		messageFile, err := bundle.ParseMessageFileBytes(buf, path)
+		if err != nil {
+			return fmt.Errorf("failed to parse message file %s: %w", path, err)
+		}
+		if err := catalogs.add(messageFile.Tag, messageFile.Messages); err != nil {
+			return fmt.Errorf("failed to parse message file %s: %w", path, err)
+		}
+	}
+
+	var tmpl *template.Template
+	if templateFile != "" {
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		tmpl, err = parseTemplate("email", string(content), parseFuncs(m.funcMap))
+		if err != nil {
+			return fmt.Errorf("failed to parse template file: %w", err)
+		}
+		if tmpl, err = applyBlocks(tmpl, m.blocks, parseFuncs(m.funcMap)); err != nil {
+			return fmt.Errorf("failed to override template block: %w", err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bundle = bundle
+	m.catalogs = catalogs
+	if tmpl != nil {
+		m.template = tmpl
+	}
+	m.generation++
+	return nil
+}
+
+// Watch polls the locale directory and template file configured in cfg and calls Reload
+// whenever a file is added, removed or modified. The files are loaded once when Watch
+// starts. Watch blocks until ctx is done and returns ctx.Err(). Reload errors are
+// reported to cfg.OnError and do not stop watching.
+//
+// Example:
+//
+//	go mailer.Watch(ctx, mailingo.WatchConfig{
+//	    LocaleDir: "/etc/app/locales",
+//	    OnError:   func(err error) { log.Printf("locale reload failed: %v", err) },
+//	})
+func (m *Mailer) Watch(ctx context.Context, cfg WatchConfig) error {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	reload := func() {
+		if err := m.Reload(cfg.LocaleDir, cfg.TemplateFile); err != nil {
+			if cfg.OnError != nil {
+				cfg.OnError(err)
+			}
+			return
+		}
+		if cfg.OnReload != nil {
+			cfg.OnReload()
+		}
+	}
+
+	last := watchFingerprint(cfg)
+	reload()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if current := watchFingerprint(cfg); current != last {
+				last = current
+				reload()
+			}
+		}
+	}
+}
+
+// messageFiles returns the sorted paths of the message files in dir
+func messageFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locale directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && filepath.Ext(entry.Name()) == ".json" && !strings.HasPrefix(entry.Name(), ".") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// watchFingerprint summarizes names, sizes and modification times of the watched files.
+// Errors are part of the fingerprint, so a directory that disappears or reappears
+// also triggers a reload.
+func watchFingerprint(cfg WatchConfig) string {
+	var b strings.Builder
+
+	var files []string
+	if cfg.LocaleDir != "" {
+		var err error
+		if files, err = messageFiles(cfg.LocaleDir); err != nil {
+			b.WriteString(err.Error())
+		}
+	}
+	if cfg.TemplateFile != "" {
+		files = append(files, cfg.TemplateFile)
+	}
+
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&b, "%s:%v\n", path, err)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package mailingo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "en.json"), `{"greeting": "Hello"}`)
+
+	mailer := New(Product{Name: "Test Product", Link: "https://example.com"}, DefaultTheme)
+	if err := mailer.Reload(dir, ""); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	email := Email{Body: Body{Name: "Test User"}}
+
+	text, err := mailer.GeneratePlainText(email, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	if !strings.Contains(text, "Hello Test User") {
+		t.Errorf("Expected reloaded greeting, got:\n%s", text)
+	}
+
+	// A broken file leaves the current messages untouched
+	writeFile(t, filepath.Join(dir, "en.json"), `{"greeting": `)
+	if err := mailer.Reload(dir, ""); err == nil {
+		t.Fatal("Reload should fail for invalid message file")
+	}
+
+	text, _ = mailer.GeneratePlainText(email, "en")
+	if !strings.Contains(text, "Hello Test User") {
+		t.Error("Failed reload should keep previous messages")
+	}
+
+	// Templates are swapped together with the messages
+	writeFile(t, filepath.Join(dir, "en.json"), `{"greeting": "Hi"}`)
+	templateFile := filepath.Join(t.TempDir(), "email.html")
+	writeFile(t, templateFile, `<p>{{.Body.Greeting}} {{.Body.Name}}</p>`)
+
+	if err := mailer.Reload(dir, templateFile); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	if html != "<p>Hi Test User</p>" {
+		t.Errorf("Expected reloaded template and messages, got %q", html)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "en.json"), `{"greeting": "Howdy"}`)
+
+	mailer := New(Product{Name: "Test Product", Link: "https://example.com"}, DefaultTheme)
+
+	var mu sync.Mutex
+	reloads := 0
+	var errs []error
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mailer.Watch(ctx, WatchConfig{
+			LocaleDir: dir,
+			Interval:  10 * time.Millisecond,
+			OnReload: func() {
+				mu.Lock()
+				reloads++
+				mu.Unlock()
+			},
+			OnError: func(err error) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			},
+		})
+	}()
+
+	email := Email{Body: Body{Name: "Test User"}}
+	waitFor(t, func() bool {
+		text, _ := mailer.GeneratePlainText(email, "en")
+		return strings.Contains(text, "Howdy Test User")
+	})
+
+	// Add a new language while rendering
+	writeFile(t, filepath.Join(dir, "zh.json"), `{"greeting": "你好呀"}`)
+	waitFor(t, func() bool {
+		text, _ := mailer.GeneratePlainText(email, "zh")
+		return strings.Contains(text, "你好呀 Test User")
+	})
+
+	// Broken files are reported without disrupting rendering
+	writeFile(t, filepath.Join(dir, "es.json"), `not json`)
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) > 0
+	})
+
+	text, _ := mailer.GeneratePlainText(email, "zh")
+	if !strings.Contains(text, "你好呀 Test User") {
+		t.Error("Reload error should keep previous messages")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if reloads < 2 {
+		t.Errorf("Expected at least 2 reloads, got %d", reloads)
+	}
+}
+
+// writeFile writes content to path, failing the test on error
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// waitFor polls cond until it returns true or the test times out
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,93 @@
+package mailingo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//go:generate go run ./internal/genschema -o email.schema.json
+
+// JSONSchemaID is the $id of the JSON Schema returned by JSONSchema
+const JSONSchemaID = "https://github.com/lib-x/mailingo/email.schema.json"
+
+// deprecatedFields lists the fields marked as deprecated in the JSON Schema
+var deprecatedFields = map[string]bool{
+	"Table.Data": true,
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the JSON form of Email.
+// The schema is derived from the json struct tags, so it always matches what
+// encoding/json produces and accepts. It is also published as email.schema.json.
+//
+// Example:
+//
+//	schema, err := mailingo.JSONSchema()
+//	os.WriteFile("email.schema.json", schema, 0o644)
+func JSONSchema() ([]byte, error) {
+	defs := map[string]interface{}{}
+	root := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     JSONSchemaID,
+		"title":   "mailingo Email",
+		"$ref":    schemaFor(reflect.TypeOf(Email{}), defs)["$ref"],
+		"$defs":   defs,
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaFor returns the schema of t, adding struct definitions to defs
+func schemaFor(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := defs[t.Name()]; ok {
+			return ref
+		}
+		defs[t.Name()] = nil // Reserve the name while the fields are resolved
+
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			property := schemaFor(field.Type, defs)
+			if deprecatedFields[t.Name()+"."+field.Name] {
+				property["deprecated"] = true
+			}
+			properties[name] = property
+		}
+
+		defs[t.Name()] = map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		return ref
+	default:
+		// interface{} and other kinds accept any value
+		return map[string]interface{}{}
+	}
+}
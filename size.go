@@ -0,0 +1,142 @@
+package mailingo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+)
+
+// GmailClipSize is the HTML size in bytes above which Gmail clips messages
+// and hides the rest behind a "View entire message" link.
+const GmailClipSize = 102 * 1024
+
+// Estimates of the bytes used by the message headers and the headers of each MIME part
+const (
+	mimeMessageOverhead = 1024
+	mimePartOverhead    = 256
+)
+
+// ErrSizeLimitExceeded is returned (wrapped) when a rendered email exceeds a maximum size limit
+var ErrSizeLimitExceeded = errors.New("size limit exceeded")
+
+// SizeLimits defines the size thresholds used by AnalyzeSize.
+// Warn thresholds add a warning to the report, Max thresholds make AnalyzeSize fail.
+// A zero or negative value disables the threshold.
+type SizeLimits struct {
+	HTMLWarn    int // Warn when the HTML exceeds this many bytes
+	HTMLMax     int // Fail when the HTML exceeds this many bytes
+	MessageWarn int // Warn when the estimated message size exceeds this many bytes
+	MessageMax  int // Fail when the estimated message size exceeds this many bytes
+}
+
+// DefaultSizeLimits warns when the HTML would be clipped by Gmail
+var DefaultSizeLimits = SizeLimits{
+	HTMLWarn: GmailClipSize,
+}
+
+// SizeReport describes the size of a rendered email
+type SizeReport struct {
+	HTMLBytes              int      // Size of the rendered HTML
+	EncodedHTMLBytes       int      // Size of the HTML after quoted-printable encoding
+	TextBytes              int      // Size of the rendered plain text
+	EncodedTextBytes       int      // Size of the plain text after quoted-printable encoding
+	AttachmentBytes        int      // Total size of the SMTP attachments
+	EncodedAttachmentBytes int      // Total size of the SMTP attachments after base64 encoding
+	MessageBytes           int      // Estimated size of the complete MIME message
+	Warnings               []string // Thresholds exceeded by the email
+}
+
+// AnalyzeSize measures the rendered HTML and plain text of an email together with its
+// SMTP attachments and checks them against the limits. The report is always returned;
+// the error wraps ErrSizeLimitExceeded if a Max threshold is exceeded.
+//
+// Example:
+//
+//	html, _ := mailer.GenerateHTML(email, "en")
+//	text, _ := mailer.GeneratePlainText(email, "en")
+//	report, err := mailingo.AnalyzeSize(html, text, email.SMTPAttachments, mailingo.DefaultSizeLimits)
+func AnalyzeSize(html, text string, attachments []SMTPAttachment, limits SizeLimits) (*SizeReport, error) {
+	report := &SizeReport{
+		HTMLBytes:        len(html),
+		EncodedHTMLBytes: quotedPrintableSize(html),
+		TextBytes:        len(text),
+		EncodedTextBytes: quotedPrintableSize(text),
+	}
+
+	parts := 0
+	if html != "" {
+		parts++
+	}
+	if text != "" {
+		parts++
+	}
+	for _, attachment := range attachments {
+		report.AttachmentBytes += len(attachment.Content)
+		report.EncodedAttachmentBytes += base64Size(len(attachment.Content))
+		parts++
+	}
+
+	report.MessageBytes = mimeMessageOverhead + parts*mimePartOverhead +
+		report.EncodedHTMLBytes + report.EncodedTextBytes + report.EncodedAttachmentBytes
+
+	var exceeded []string
+	if limits.HTMLWarn > 0 && report.HTMLBytes > limits.HTMLWarn {
+		warning := fmt.Sprintf("HTML is %d bytes, exceeds %d bytes", report.HTMLBytes, limits.HTMLWarn)
+		if report.HTMLBytes > GmailClipSize {
+			warning += " and will be clipped by Gmail"
+		}
+		report.Warnings = append(report.Warnings, warning)
+	}
+	if limits.HTMLMax > 0 && report.HTMLBytes > limits.HTMLMax {
+		exceeded = append(exceeded, fmt.Sprintf("HTML is %d bytes, limit is %d bytes", report.HTMLBytes, limits.HTMLMax))
+	}
+	if limits.MessageWarn > 0 && report.MessageBytes > limits.MessageWarn {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("message is about %d bytes, exceeds %d bytes", report.MessageBytes, limits.MessageWarn))
+	}
+	if limits.MessageMax > 0 && report.MessageBytes > limits.MessageMax {
+		exceeded = append(exceeded, fmt.Sprintf("message is about %d bytes, limit is %d bytes", report.MessageBytes, limits.MessageMax))
+	}
+
+	if len(exceeded) > 0 {
+		return report, fmt.Errorf("%w: %s", ErrSizeLimitExceeded, strings.Join(exceeded, "; "))
+	}
+	return report, nil
+}
+
+// AnalyzeSize measures the rendered HTML and plain text of the email together with its
+// SMTP attachments, using the size limits configured with the options package.
+func (m *Mailer) AnalyzeSize(email Email, html, text string) (*SizeReport, error) {
+	return AnalyzeSize(html, text, email.SMTPAttachments, m.sizeLimits)
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
+
+// quotedPrintableSize returns the size of s after quoted-printable encoding
+func quotedPrintableSize(s string) int {
+	if s == "" {
+		return 0
+	}
+	counter := &countingWriter{}
+	qp := quotedprintable.NewWriter(counter)
+	io.WriteString(qp, s)
+	qp.Close()
+	return counter.n
+}
+
+// base64Size returns the size of n bytes after base64 encoding with 76 character lines
+func base64Size(n int) int {
+	encoded := (n + 2) / 3 * 4
+	lines := (encoded + 75) / 76
+	return encoded + lines*2
+}
@@ -0,0 +1,116 @@
+package mailingo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestAnalyzeSize(t *testing.T) {
+	html := "<p>Hello</p>"
+	text := "Hello"
+	attachments := []SMTPAttachment{
+		{Filename: "a.bin", Content: make([]byte, 300)},
+	}
+
+	report, err := AnalyzeSize(html, text, attachments, DefaultSizeLimits)
+	if err != nil {
+		t.Fatalf("AnalyzeSize failed: %v", err)
+	}
+
+	if report.HTMLBytes != len(html) {
+		t.Errorf("Expected HTML size %d, got %d", len(html), report.HTMLBytes)
+	}
+
+	if report.EncodedHTMLBytes != len(html) {
+		t.Errorf("Plain ASCII HTML should not grow when encoded, got %d", report.EncodedHTMLBytes)
+	}
+
+	if report.AttachmentBytes != 300 {
+		t.Errorf("Expected attachment size 300, got %d", report.AttachmentBytes)
+	}
+
+	// 300 bytes -> 400 base64 characters -> 6 lines of CRLF
+	if report.EncodedAttachmentBytes != 412 {
+		t.Errorf("Expected encoded attachment size 412, got %d", report.EncodedAttachmentBytes)
+	}
+
+	if report.MessageBytes <= report.EncodedHTMLBytes+report.EncodedTextBytes+report.EncodedAttachmentBytes {
+		t.Error("Message size should include MIME overhead")
+	}
+
+	if len(report.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", report.Warnings)
+	}
+}
+
+func TestAnalyzeSizeLimits(t *testing.T) {
+	html := strings.Repeat("x", GmailClipSize+1)
+
+	report, err := AnalyzeSize(html, "", nil, DefaultSizeLimits)
+	if err != nil {
+		t.Fatalf("Warn thresholds should not return an error: %v", err)
+	}
+
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "clipped by Gmail") {
+		t.Errorf("Expected Gmail clipping warning, got %v", report.Warnings)
+	}
+
+	report, err = AnalyzeSize(html, "", nil, SizeLimits{HTMLMax: GmailClipSize})
+	if !errors.Is(err, ErrSizeLimitExceeded) {
+		t.Errorf("Expected ErrSizeLimitExceeded, got %v", err)
+	}
+
+	if report == nil {
+		t.Error("Report should be returned along with the error")
+	}
+
+	_, err = AnalyzeSize("", "", []SMTPAttachment{{Content: make([]byte, 2048)}}, SizeLimits{MessageMax: 2048})
+	if !errors.Is(err, ErrSizeLimitExceeded) {
+		t.Errorf("Expected message size limit to be exceeded, got %v", err)
+	}
+}
+
+func TestMailerAnalyzeSize(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	rows := make([]TableRow, 2000)
+	for i := range rows {
+		rows[i] = TableRow{Cells: []string{"Wireless Headphones", "1", "$79.99"}}
+	}
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+			Table: Table{
+				Header: []string{"Product", "Quantity", "Price"},
+				Rows:   rows,
+			},
+		},
+	}
+
+	mailer := New(product, DefaultTheme)
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	report, err := mailer.AnalyzeSize(email, html, "")
+	if err != nil {
+		t.Fatalf("AnalyzeSize failed: %v", err)
+	}
+
+	if len(report.Warnings) == 0 {
+		t.Error("Large table should trigger the default Gmail clipping warning")
+	}
+
+	mailer = New(product, DefaultTheme, options.WithHTMLSizeLimits(-1, GmailClipSize))
+	if _, err := mailer.AnalyzeSize(email, html, ""); !errors.Is(err, ErrSizeLimitExceeded) {
+		t.Errorf("Expected ErrSizeLimitExceeded with configured HTML max, got %v", err)
+	}
+}
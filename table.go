@@ -0,0 +1,140 @@
+package mailingo
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// tableView is the translated, normalized form of a Table used for rendering
+type tableView struct {
+	Header     []string   // Header cells, including the row key column header if HasRowKeys
+	Rows       []TableRow // Body rows
+	HasRowKeys bool       // Whether rows have a leading key column
+	Data       [][]Entry  // Translated legacy table data, for templates that still use it
+	Columns    Columns    // Column definitions
+}
+
+// processTable translates the table and normalizes the legacy Data form into header and rows.
+// Legacy cells translate their Key like before; header cells without a Key fall back to their
+// Value and body cells without a Value fall back to their translated Key, so content is never
+// silently dropped.
+func (m *Mailer) processTable(table Table, localizer *localizer, data map[string]interface{}) tableView {
+	var view tableView
+
+	if len(table.Data) > 0 {
+		view.Data = make([][]Entry, len(table.Data))
+		for i, row := range table.Data {
+			view.Data[i] = make([]Entry, len(row))
+			for j, cell := range row {
+				view.Data[i][j] = Entry{
+					Key:   m.translate(localizer, cell.Key, "", data),
+					Value: cell.Value,
+				}
+			}
+		}
+
+		for _, cell := range view.Data[0] {
+			text := cell.Key
+			if text == "" {
+				text = cell.Value
+			}
+			view.Header = append(view.Header, text)
+		}
+		for _, row := range view.Data[1:] {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = cell.Value
+				if cells[i] == "" {
+					cells[i] = cell.Key
+				}
+			}
+			view.Rows = append(view.Rows, TableRow{Cells: cells})
+		}
+		return view
+	}
+
+	for _, row := range table.Rows {
+		if row.Key != "" {
+			view.HasRowKeys = true
+			break
+		}
+	}
+
+	// The key column only gets a header cell if the table has a header
+	if view.HasRowKeys && (len(table.Header) > 0 || table.KeyHeader != "") {
+		view.Header = append(view.Header, m.translate(localizer, table.KeyHeader, "", data))
+	}
+	for _, header := range table.Header {
+		view.Header = append(view.Header, m.translate(localizer, header, "", data))
+	}
+
+	view.Rows = make([]TableRow, len(table.Rows))
+	for i, row := range table.Rows {
+		view.Rows[i] = TableRow{
+			Key:   m.translate(localizer, row.Key, "", data),
+			Cells: row.Cells,
+		}
+	}
+	return view
+}
+
+// writePlainTextTable writes the table with aligned columns
+func writePlainTextTable(buf io.StringWriter, table tableView) {
+	lines := make([][]string, 0, len(table.Rows)+1)
+	if len(table.Header) > 0 {
+		lines = append(lines, table.Header)
+	}
+	for _, row := range table.Rows {
+		var line []string
+		if table.HasRowKeys {
+			line = append(line, row.Key)
+		}
+		lines = append(lines, append(line, row.Cells...))
+	}
+
+	// Compute column widths
+	var widths []int
+	for _, line := range lines {
+		for i, cell := range line {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	writeLine := func(line []string) {
+		for i, cell := range line {
+			if i > 0 {
+				buf.WriteString(" | ")
+			}
+			buf.WriteString(cell)
+			if i < len(line)-1 {
+				buf.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			}
+		}
+		buf.WriteString("\n")
+	}
+
+	for i, line := range lines {
+		writeLine(line)
+		if i == 0 && len(table.Header) > 0 {
+			separators := make([]string, len(table.Header))
+			for j := range separators {
+				separators[j] = strings.Repeat("-", widths[j])
+			}
+			buf.WriteString(strings.Join(separators, "-+-"))
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// plainTextTable returns the table formatted by writePlainTextTable, for the textTable template function
+func plainTextTable(table tableView) string {
+	var buf strings.Builder
+	writePlainTextTable(&buf, table)
+	return buf.String()
+}
@@ -0,0 +1,168 @@
+package mailingo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypedTableRendering(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+			Table: Table{
+				KeyHeader: "Plan",
+				Header:    []string{"Monthly", "Yearly"},
+				Rows: []TableRow{
+					{Key: "Basic", Cells: []string{"$10", "$100"}},
+					{Key: "Pro", Cells: []string{"$20", "$200"}},
+				},
+			},
+		},
+	}
+
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	for _, s := range []string{"<th>Plan</th>", "<th>Monthly</th>", `<th scope="row" class="email-table-row-key">Pro</th>`, "<td>$200</td>"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("HTML should contain %s", s)
+		}
+	}
+
+	text, err := mailer.GeneratePlainText(email, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	expected := "Plan  | Monthly | Yearly\n" +
+		"------+---------+-------\n" +
+		"Basic | $10     | $100\n" +
+		"Pro   | $20     | $200\n"
+	if !strings.Contains(text, expected) {
+		t.Errorf("Plain text should contain aligned table, got:\n%s", text)
+	}
+}
+
+func TestLegacyTableCellFallback(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+			Table: Table{
+				Data: [][]Entry{
+					{{Key: "Item"}, {Value: "Price"}},
+					{{Value: "Widget"}, {Key: "$9.99"}},
+				},
+			},
+		},
+	}
+
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	// Header cells with only a Value and body cells with only a Key must not disappear
+	if !strings.Contains(html, "<th>Price</th>") {
+		t.Error("HTML should render header cell Value when Key is empty")
+	}
+
+	if !strings.Contains(html, "<td>$9.99</td>") {
+		t.Error("HTML should render body cell Key when Value is empty")
+	}
+
+	text, err := mailer.GeneratePlainText(email, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	if !strings.Contains(text, "Widget | $9.99") {
+		t.Errorf("Plain text should contain table rows, got:\n%s", text)
+	}
+}
+
+func TestTableRowKeysWithoutHeader(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+			Table: Table{
+				Rows: []TableRow{
+					{Key: "Basic", Cells: []string{"$10", "$100"}},
+					{Key: "Pro", Cells: []string{"$20", "$200"}},
+				},
+			},
+		},
+	}
+
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	if strings.Contains(html, "<thead>") {
+		t.Error("HTML should not render a header for a table without header cells")
+	}
+
+	text, err := mailer.GeneratePlainText(email, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	expected := "Basic | $10 | $100\nPro   | $20 | $200\n"
+	if !strings.Contains(text, expected) {
+		t.Errorf("Plain text should contain the rows without a header, got:\n%s", text)
+	}
+	if strings.Contains(text, "---") {
+		t.Errorf("Plain text should not contain a header separator, got:\n%s", text)
+	}
+}
+
+func TestTypedTableValidation(t *testing.T) {
+	email := Email{
+		Body: Body{
+			Table: Table{
+				Header: []string{"Item", "Price"},
+				Rows: []TableRow{
+					{Cells: []string{"Widget", "$9.99"}},
+					{Cells: []string{"Gadget"}},
+				},
+			},
+		},
+	}
+
+	err := email.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error for short row")
+	}
+
+	if !strings.Contains(err.Error(), "Body.Table.Rows[1]: row has 1 cells, header has 2") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	email.Body.Table.Data = [][]Entry{{{Key: "Item"}}}
+	if err := email.Validate(); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("Expected error for combined Data and Header/Rows, got: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package mailingo
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Tenant configures a tenant view created with Mailer.Tenant
+type Tenant struct {
+	Product   Product                      `json:"product,omitzero" yaml:"product,omitempty"`        // Product fields of the tenant (empty fields keep the base product's)
+	Theme     Theme                        `json:"theme,omitzero" yaml:"theme,omitempty"`            // Theme fields of the tenant (empty fields keep the base theme's)
+	CustomCSS string                       `json:"custom_css,omitempty" yaml:"custom_css,omitempty"` // CSS added after the base custom CSS
+	Messages  map[string]map[string]string `json:"messages,omitempty" yaml:"messages,omitempty"`     // Message overrides by language, e.g. {"en": {"greeting": "Hi"}}
+}
+
+// Tenant returns a view of the Mailer for a tenant of a white-label product. The view
+// renders with the tenant's product, theme and message overrides, and shares the
+// messages, templates and options of the Mailer, so creating a view is cheap and
+// messages loaded into the Mailer later are visible to all its views.
+//
+// The non-empty fields of the tenant's product and theme replace those of the Mailer,
+// so e.g. a tenant with only a product name keeps the link and logo of the Mailer.
+//
+// An override replaces a message of the same ID in the same language, and is looked
+// up in the requested language and then in every language the Mailer looks up,
+// including the languages of fallback chains configured with options.WithFallback.
+// An override of a message with plural forms is used for every count.
+// Overrides of a view created from another view are layered on top of its overrides.
+//
+// Messages added through a view with AddMessages, LoadMessageFile, LoadMessageFileFS
+// or Reload are loaded into the shared messages of the Mailer, not as overrides.
+//
+// Example:
+//
+//	acme, err := mailer.Tenant(mailingo.Tenant{
+//	    Product:  mailingo.Product{Name: "Acme", Logo: "https://acme.com/logo.png"},
+//	    Theme:    mailingo.FlatTheme,
+//	    Messages: map[string]map[string]string{"en": {"signature": "The Acme Team"}},
+//	})
+//	html, err := acme.GenerateHTML(email, "en")
+func (m *Mailer) Tenant(tenant Tenant) (*Mailer, error) {
+	sources := append(slices.Clip(m.overrideSources), tenant.Messages)
+	overrides := make(exactCatalogs)
+	for _, source := range sources {
+		for lang, messages := range source {
+			tag, err := language.Parse(lang)
+			if err != nil {
+				return nil, fmt.Errorf("invalid language tag %q: %w", lang, err)
+			}
+
+			msgs := make([]*i18n.Message, 0, len(messages))
+			for id, text := range messages {
+				// Overrides have no plural forms, so every form uses the text
+				msgs = append(msgs, &i18n.Message{ID: id, Zero: text, One: text, Two: text, Few: text, Many: text, Other: text})
+			}
+			if err := overrides.add(tag, msgs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	view := &Mailer{
+		shared:     m.shared,
+		localizers: newLRUCache[string, *localizer](maxCachedLocalizers),
+
+		product:   mergeProduct(m.product, tenant.Product),
+		theme:     mergeTheme(m.theme, tenant.Theme),
+		customCSS: joinCSS(m.customCSS, tenant.CustomCSS),
+
+		overrides:       overrides,
+		overrideSources: sources,
+
+		trackingURL:                 m.trackingURL,
+		trackingSuppressedLanguages: m.trackingSuppressedLanguages,
+		trackingSuppressor:          m.trackingSuppressor,
+
+		validate:      m.validate,
+		validateLinks: m.validateLinks,
+		linkPolicy:    m.linkPolicy,
+
+		sizeLimits: m.sizeLimits,
+	}
+	return view, nil
+}
+
+// mergeProduct returns the base product with the non-empty fields of the tenant's product
+func mergeProduct(base, product Product) Product {
+	return Product{
+		Name:      cmp.Or(product.Name, base.Name),
+		Link:      cmp.Or(product.Link, base.Link),
+		Logo:      cmp.Or(product.Logo, base.Logo),
+		Copyright: cmp.Or(product.Copyright, base.Copyright),
+	}
+}
+
+// mergeTheme returns the base theme with the non-empty fields of the tenant's theme
+func mergeTheme(base, theme Theme) Theme {
+	return Theme{
+		PrimaryColor:    cmp.Or(theme.PrimaryColor, base.PrimaryColor),
+		BackgroundColor: cmp.Or(theme.BackgroundColor, base.BackgroundColor),
+		TextColor:       cmp.Or(theme.TextColor, base.TextColor),
+		ButtonColor:     cmp.Or(theme.ButtonColor, base.ButtonColor),
+		ButtonTextColor: cmp.Or(theme.ButtonTextColor, base.ButtonTextColor),
+	}
+}
+
+// joinCSS appends the CSS of a tenant to the base CSS
+func joinCSS(base, css string) string {
+	if base == "" || css == "" {
+		return base + css
+	}
+	return base + "\n" + css
+}
+
+// newOverrides creates the localizers of the tenant overrides for lang, looked up
+// before the shared catalogs of the same languages
+func (m *Mailer) newOverrides(lang string, lookup []language.Tag) []chainLink {
+	if len(m.overrides) == 0 {
+		return nil
+	}
+
+	tags := lookup
+	if tag, err := language.Parse(lang); err == nil {
+		tags = append([]language.Tag{tag}, lookup...)
+	}
+
+	var links []chainLink
+	seen := make(map[language.Tag]bool, len(tags))
+	for _, tag := range tags {
+		if bundle, ok := m.overrides[tag]; ok && !seen[tag] {
+			seen[tag] = true
+			links = append(links, chainLink{tag: tag, localizer: i18n.NewLocalizer(bundle, tag.String())})
+		}
+	}
+	return links
+}
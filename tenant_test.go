@@ -0,0 +1,188 @@
+package mailingo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestTenant(t *testing.T) {
+	base := New(Product{Name: "Base", Copyright: "product.copyright"}, DefaultTheme)
+	base.AddMessages("en", map[string]string{
+		"email.title":       "Welcome",
+		"product.copyright": "© {{.Year}} Base",
+	})
+	base.AddMessages("es", map[string]string{"email.title": "Bienvenido"})
+
+	acme, err := base.Tenant(Tenant{
+		Product:   Product{Name: "Acme", Copyright: "product.copyright"},
+		Theme:     FlatTheme,
+		CustomCSS: ".acme { color: red; }",
+		Messages: map[string]map[string]string{
+			"en": {"product.copyright": "© Acme Inc.", "signature": "The Acme Team"},
+			"es": {"signature": "El equipo de Acme"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Tenant failed: %v", err)
+	}
+
+	email := Email{Body: Body{Name: "Jane", Title: "email.title"}}
+	html, err := acme.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	for _, s := range []string{"Welcome", "© Acme Inc.", "The Acme Team", FlatTheme.PrimaryColor, ".acme { color: red; }"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("Tenant HTML should contain %q", s)
+		}
+	}
+
+	if got := acme.Translate("signature", "es-AR", nil); got != "El equipo de Acme" {
+		t.Errorf("Override should apply to the language the messages resolve to, got %q", got)
+	}
+	if got := acme.Translate("email.title", "es", nil); got != "Bienvenido" {
+		t.Errorf("Messages without override should come from the base, got %q", got)
+	}
+
+	html, err = base.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Acme") || !strings.Contains(html, "Best regards") {
+		t.Error("Base mailer should not see tenant overrides")
+	}
+}
+
+func TestTenantSharesMessages(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"email.title": "Welcome"}`), 0o644)
+
+	base := New(Product{Name: "Base"}, DefaultTheme)
+	acme, err := base.Tenant(Tenant{Product: Product{Name: "Acme"}})
+	if err != nil {
+		t.Fatalf("Tenant failed: %v", err)
+	}
+
+	if got := acme.Translate("email.title", "en", nil); got != "email.title" {
+		t.Errorf("Expected message ID before loading, got %q", got)
+	}
+	if err := base.Reload(dir, ""); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := acme.Translate("email.title", "en", nil); got != "Welcome" {
+		t.Errorf("Tenant should see messages reloaded into the base, got %q", got)
+	}
+
+	base.AddMessages("en", map[string]string{"email.title": "Hello there"})
+	if got := acme.Translate("email.title", "en", nil); got != "Hello there" {
+		t.Errorf("Tenant should see messages added to the base, got %q", got)
+	}
+}
+
+func TestTenantLayers(t *testing.T) {
+	base := New(Product{Name: "Base"}, DefaultTheme, options.WithFallback("en-x-acme", "en"))
+	base.AddMessages("en", map[string]string{"greeting": "Hello", "signature": "Regards"})
+
+	acme, err := base.Tenant(Tenant{Messages: map[string]map[string]string{"en": {"greeting": "Hi", "signature": "Cheers"}}})
+	if err != nil {
+		t.Fatalf("Tenant failed: %v", err)
+	}
+	team, err := acme.Tenant(Tenant{Messages: map[string]map[string]string{"en": {"signature": "The Team"}}})
+	if err != nil {
+		t.Fatalf("Tenant failed: %v", err)
+	}
+
+	if got := team.Translate("greeting", "en", nil); got != "Hi" {
+		t.Errorf("Nested view should keep parent overrides, got %q", got)
+	}
+	if got := team.Translate("signature", "en-x-acme", nil); got != "The Team" {
+		t.Errorf("Nested view overrides should win, got %q", got)
+	}
+	if got := acme.Translate("signature", "en", nil); got != "Cheers" {
+		t.Errorf("Parent view should be unchanged, got %q", got)
+	}
+	if team.product.Name != "Base" {
+		t.Errorf("Zero product should keep the base product, got %q", team.product.Name)
+	}
+
+	trace := team.TraceMessage("signature", "en", nil)
+	if !trace.Override || trace.String() != `"signature" en: tenant en (chain en)` {
+		t.Errorf("Unexpected trace %v", trace)
+	}
+
+	if _, err := base.Tenant(Tenant{Messages: map[string]map[string]string{"not a tag": {}}}); err == nil {
+		t.Error("Invalid override language should fail")
+	}
+}
+
+func TestTenantTemplateFuncs(t *testing.T) {
+	base := New(Product{Name: "Base", Link: "https://base.example"}, DefaultTheme,
+		options.WithCustomTemplateString(`{{.Product.Name}} {{.Product.Link}}|{{t "signature"}}|{{tn "cart.items" 1}}`))
+	err := base.LoadMessageFileFS(fstest.MapFS{
+		"en.json": {Data: []byte(`{"signature": "Regards", "cart.items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}}`)},
+	}, "en.json")
+	if err != nil {
+		t.Fatalf("LoadMessageFileFS failed: %v", err)
+	}
+
+	acme, err := base.Tenant(Tenant{
+		Product:  Product{Name: "Acme"},
+		Messages: map[string]map[string]string{"en": {"signature": "Cheers", "cart.items": "{{.Count}} Acme product(s)"}},
+	})
+	if err != nil {
+		t.Fatalf("Tenant failed: %v", err)
+	}
+
+	html, err := acme.GenerateHTML(Email{}, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if want := "Acme https://base.example|Cheers|1 Acme product(s)"; html != want {
+		t.Errorf("Expected %q, got %q", want, html)
+	}
+	if got := acme.TranslatePlural("cart.items", "en", 2, nil); got != "2 Acme product(s)" {
+		t.Errorf("TranslatePlural should use the override, got %q", got)
+	}
+
+	html, err = base.GenerateHTML(Email{}, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if want := "Base https://base.example|Regards|1 item"; html != want {
+		t.Errorf("Expected %q, got %q", want, html)
+	}
+
+	if base.boundTemplates.len() != 1 {
+		t.Errorf("Tenant views should share the bound templates, got %d", base.boundTemplates.len())
+	}
+}
+
+func TestTenantConcurrent(t *testing.T) {
+	base := New(Product{Name: "Base"}, DefaultTheme)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			base.AddMessages("en", map[string]string{"email.title": "Welcome"})
+		}()
+		go func() {
+			defer wg.Done()
+			view, err := base.Tenant(Tenant{Product: Product{Name: "Acme"}})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := view.GenerateHTML(Email{Body: Body{Title: "email.title"}}, "en"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,119 @@
+package mailingo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// transparentGIF is a 1x1 transparent GIF image served by the tracking handler
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// OpenEvent describes a single open of a tracked email
+type OpenEvent struct {
+	TrackingID string    // TrackingID of the opened email
+	Time       time.Time // Time the pixel was requested
+	UserAgent  string    // User-Agent header of the request
+	RemoteAddr string    // Network address of the client
+}
+
+// OpenRecorder receives open events from the tracking handler.
+// Implementations should return quickly, as the pixel response waits for RecordOpen.
+type OpenRecorder interface {
+	RecordOpen(ctx context.Context, event OpenEvent)
+}
+
+// OpenRecorderFunc adapts an ordinary function to the OpenRecorder interface
+type OpenRecorderFunc func(ctx context.Context, event OpenEvent)
+
+// RecordOpen calls f(ctx, event)
+func (f OpenRecorderFunc) RecordOpen(ctx context.Context, event OpenEvent) {
+	f(ctx, event)
+}
+
+// NewTrackingHandler returns an http.Handler serving the transparent tracking pixel.
+// Mount it at the URL passed to options.WithTrackingPixel. Every request carrying an
+// "id" query parameter is reported to the recorder; the pixel is served either way.
+//
+// Example:
+//
+//	http.Handle("/open.gif", mailingo.NewTrackingHandler(recorder))
+func NewTrackingHandler(recorder OpenRecorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("id"); id != "" && recorder != nil {
+			recorder.RecordOpen(r.Context(), OpenEvent{
+				TrackingID: id,
+				Time:       time.Now(),
+				UserAgent:  r.UserAgent(),
+				RemoteAddr: r.RemoteAddr,
+			})
+		}
+
+		w.Header().Set("Content-Type", "image/gif")
+		w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
+		w.Header().Set("Pragma", "no-cache")
+		w.Header().Set("Expires", "0")
+		w.Write(transparentGIF)
+	})
+}
+
+// trackingPixelURL returns the tracking pixel URL for the email, or an empty string
+// if tracking is disabled, the email has no TrackingID or tracking is suppressed.
+func (m *Mailer) trackingPixelURL(email Email, lang string) string {
+	if m.trackingURL == "" || email.TrackingID == "" || email.DisableTracking {
+		return ""
+	}
+	if matchesLanguage(lang, m.trackingSuppressedLanguages) {
+		return ""
+	}
+	if m.trackingSuppressor != nil && m.trackingSuppressor(lang, email.TrackingID) {
+		return ""
+	}
+
+	u, err := url.Parse(m.trackingURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	query.Set("id", email.TrackingID)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// matchesLanguage reports whether lang matches any of the patterns.
+// A pattern without a region matches every region of its language,
+// a pattern with a region only matches that exact region.
+func matchesLanguage(lang string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return false
+	}
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+
+	for _, pattern := range patterns {
+		patternTag, err := language.Parse(pattern)
+		if err != nil {
+			continue
+		}
+		patternBase, _ := patternTag.Base()
+		if patternBase != base {
+			continue
+		}
+		patternRegion, confidence := patternTag.Region()
+		if confidence != language.Exact || patternRegion == region {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package mailingo
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestTrackingPixel(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme, options.WithTrackingPixel("https://track.example.com/open.gif"))
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+		},
+		TrackingID: "msg-123",
+	}
+
+	html, err := mailer.GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	if !strings.Contains(html, "https://track.example.com/open.gif?id=msg-123") {
+		t.Error("HTML should contain tracking pixel URL")
+	}
+
+	text, err := mailer.GeneratePlainText(email, "en")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+
+	if strings.Contains(text, "track.example.com") {
+		t.Error("Plain text should not contain tracking pixel")
+	}
+}
+
+func TestTrackingPixelSuppression(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme,
+		options.WithTrackingPixel("https://track.example.com/open.gif"),
+		options.WithTrackingSuppressedLanguages("de", "fr-FR"),
+		options.WithTrackingSuppressor(func(lang, trackingID string) bool {
+			return trackingID == "no-consent"
+		}),
+	)
+
+	tests := []struct {
+		name    string
+		email   Email
+		lang    string
+		tracked bool
+	}{
+		{"Tracked", Email{TrackingID: "msg-1"}, "en", true},
+		{"NoTrackingID", Email{}, "en", false},
+		{"DisabledPerEmail", Email{TrackingID: "msg-1", DisableTracking: true}, "en", false},
+		{"SuppressedLanguage", Email{TrackingID: "msg-1"}, "de", false},
+		{"SuppressedLanguageAnyRegion", Email{TrackingID: "msg-1"}, "de-AT", false},
+		{"SuppressedRegion", Email{TrackingID: "msg-1"}, "fr-FR", false},
+		{"OtherRegion", Email{TrackingID: "msg-1"}, "fr-CA", true},
+		{"Suppressor", Email{TrackingID: "no-consent"}, "en", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := mailer.GenerateHTML(tt.email, tt.lang)
+			if err != nil {
+				t.Fatalf("GenerateHTML failed: %v", err)
+			}
+
+			tracked := strings.Contains(html, "track.example.com")
+			if tracked != tt.tracked {
+				t.Errorf("Expected tracked=%v, got %v", tt.tracked, tracked)
+			}
+		})
+	}
+}
+
+func TestTrackingPixelDisabledByDefault(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme)
+
+	html, err := mailer.GenerateHTML(Email{TrackingID: "msg-1"}, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	if strings.Contains(html, `width="1" height="1"`) {
+		t.Error("HTML should not contain tracking pixel without WithTrackingPixel")
+	}
+}
+
+func TestTrackingHandler(t *testing.T) {
+	var events []OpenEvent
+	handler := NewTrackingHandler(OpenRecorderFunc(func(ctx context.Context, event OpenEvent) {
+		events = append(events, event)
+	}))
+
+	req := httptest.NewRequest("GET", "/open.gif?id=msg-123", nil)
+	req.Header.Set("User-Agent", "TestMail/1.0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Type") != "image/gif" {
+		t.Errorf("Expected image/gif content type, got %s", rec.Header().Get("Content-Type"))
+	}
+
+	if !strings.HasPrefix(rec.Body.String(), "GIF89a") {
+		t.Error("Response should be a GIF image")
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+
+	if events[0].TrackingID != "msg-123" {
+		t.Errorf("Expected tracking ID msg-123, got %s", events[0].TrackingID)
+	}
+
+	if events[0].UserAgent != "TestMail/1.0" {
+		t.Errorf("Expected user agent TestMail/1.0, got %s", events[0].UserAgent)
+	}
+
+	// Requests without an ID still get the pixel but are not recorded
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/open.gif", nil))
+
+	if len(events) != 1 {
+		t.Errorf("Requests without ID should not be recorded, got %d events", len(events))
+	}
+}
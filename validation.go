@@ -0,0 +1,252 @@
+package mailingo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FieldError describes a validation failure of a single Email field
+type FieldError struct {
+	Field   string // Path of the field, e.g. "Body.Actions[1].Button.Link"
+	Message string // Human-readable description of the problem
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError is returned when an Email fails validation.
+// It collects every field error found, so all problems can be fixed at once.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Error()
+	}
+	return fmt.Sprintf("invalid email: %s", strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual field errors for use with errors.Is and errors.As
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// add records a field error
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns e if any field errors were recorded, nil otherwise
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the structure of the email and returns a *ValidationError
+// listing every problem found, or nil. It catches content that would otherwise
+// render silently but incorrectly, such as actions without links, attachments
+// without URLs and table rows that do not match the header row.
+func (e Email) Validate() error {
+	verr := &ValidationError{}
+	validateEmail(verr, e)
+	return verr.err()
+}
+
+// Validate checks the structure of the email like Email.Validate, and additionally
+// checks all links against the link policy configured with the options package
+// and that the email's layout is registered.
+func (m *Mailer) Validate(email Email) error {
+	verr := &ValidationError{}
+	validateEmail(verr, email)
+	validateLinks(verr, email, m.linkPolicy)
+	if email.Layout != "" && !m.HasLayout(email.Layout) {
+		verr.add("Layout", "unknown layout %q", email.Layout)
+	}
+	return verr.err()
+}
+
+// validateEmail records structural problems of the email in verr
+func validateEmail(verr *ValidationError, email Email) {
+	body := email.Body
+
+	for i, entry := range body.Dictionary {
+		if entry.Key == "" {
+			verr.add(fmt.Sprintf("Body.Dictionary[%d].Key", i), "key is required")
+		}
+	}
+
+	validateVariant(verr, "Body.Formality", body.Formality)
+	validateVariant(verr, "Body.Gender", body.Gender)
+
+	validateTable(verr, body.Table)
+
+	for i, action := range body.Actions {
+		if action.Button.Text == "" {
+			verr.add(fmt.Sprintf("Body.Actions[%d].Button.Text", i), "button text is required")
+		}
+		if action.Button.Link == "" {
+			verr.add(fmt.Sprintf("Body.Actions[%d].Button.Link", i), "button link is required")
+		}
+	}
+
+	for i, attachment := range body.Attachments {
+		if attachment.Name == "" {
+			verr.add(fmt.Sprintf("Body.Attachments[%d].Name", i), "name is required")
+		}
+		if attachment.URL == "" {
+			verr.add(fmt.Sprintf("Body.Attachments[%d].URL", i), "URL is required")
+		}
+	}
+
+	for i, attachment := range email.SMTPAttachments {
+		if attachment.Filename == "" {
+			verr.add(fmt.Sprintf("SMTPAttachments[%d].Filename", i), "filename is required")
+		}
+		if len(attachment.Content) == 0 {
+			verr.add(fmt.Sprintf("SMTPAttachments[%d].Content", i), "content is empty")
+		}
+	}
+}
+
+// validateVariant records a recipient attribute that cannot be used as a message ID suffix
+func validateVariant(verr *ValidationError, field, value string) {
+	if strings.ContainsAny(value, ". \t\n") {
+		verr.add(field, "%q must not contain dots or spaces", value)
+	}
+}
+
+// validateTable records table problems in verr
+func validateTable(verr *ValidationError, table Table) {
+	if len(table.Data) > 0 {
+		if len(table.Header) > 0 || len(table.Rows) > 0 {
+			verr.add("Body.Table.Data", "cannot be combined with Header and Rows")
+		}
+
+		header := table.Data[0]
+		if len(header) == 0 {
+			verr.add("Body.Table.Data[0]", "header row is empty")
+		}
+		for i, row := range table.Data[1:] {
+			if len(row) != len(header) {
+				verr.add(fmt.Sprintf("Body.Table.Data[%d]", i+1), "row has %d cells, header has %d", len(row), len(header))
+			}
+		}
+		return
+	}
+
+	for i, row := range table.Rows {
+		if len(table.Header) > 0 && len(row.Cells) != len(table.Header) {
+			verr.add(fmt.Sprintf("Body.Table.Rows[%d]", i), "row has %d cells, header has %d", len(row.Cells), len(table.Header))
+		}
+	}
+
+	if len(table.Header) == 0 && len(table.Rows) > 1 {
+		for i, row := range table.Rows[1:] {
+			if len(row.Cells) != len(table.Rows[0].Cells) {
+				verr.add(fmt.Sprintf("Body.Table.Rows[%d]", i+1), "row has %d cells, first row has %d", len(row.Cells), len(table.Rows[0].Cells))
+			}
+		}
+	}
+}
+
+// LinkPolicy defines which URLs are accepted for Button.Link and Attachment.URL.
+// The zero value only accepts absolute HTTPS URLs.
+type LinkPolicy struct {
+	AllowInsecure bool // Accept plain HTTP URLs
+	AllowRelative bool // Accept relative URLs (e.g., "/account"), but not protocol-relative ones (e.g., "//example.com" or "/\example.com")
+	AllowMailto   bool // Accept mailto: URLs
+}
+
+// unsafeSchemes are rejected regardless of the policy
+var unsafeSchemes = map[string]bool{
+	"javascript": true,
+	"vbscript":   true,
+	"data":       true,
+	"file":       true,
+}
+
+// Check returns an error describing why link is not accepted by the policy, or nil.
+// Empty links are accepted; use Email.Validate to require them.
+func (p LinkPolicy) Check(link string) error {
+	if link == "" {
+		return nil
+	}
+
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return fmt.Errorf("invalid URL %q", link)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	switch {
+	case unsafeSchemes[scheme]:
+		return fmt.Errorf("unsafe URL scheme %q", scheme)
+	case scheme == "" && u.Host != "":
+		return fmt.Errorf("protocol-relative URL %q is not allowed, use https", link)
+	case scheme == "" && (strings.HasPrefix(u.Path, `\`) || strings.HasPrefix(u.Path, `/\`)):
+		// Browsers read backslashes as slashes, making e.g. /\evil.example protocol-relative
+		return fmt.Errorf("relative URL %q is not allowed, browsers may read its backslash as a slash", link)
+	case scheme == "":
+		if !p.AllowRelative {
+			return fmt.Errorf("relative URL %q is not allowed", link)
+		}
+		return nil
+	case scheme == "https":
+	case scheme == "http":
+		if !p.AllowInsecure {
+			return fmt.Errorf("insecure URL %q, use https", link)
+		}
+	case scheme == "mailto":
+		if !p.AllowMailto {
+			return fmt.Errorf("mailto URL %q is not allowed", link)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported URL scheme %q", scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("URL %q has no host", link)
+	}
+	return nil
+}
+
+// ValidateLinks checks every Button.Link and Attachment.URL of the email against the policy.
+// It returns a *ValidationError listing each rejected link by its field path, or nil.
+func ValidateLinks(email Email, policy LinkPolicy) error {
+	verr := &ValidationError{}
+	validateLinks(verr, email, policy)
+	return verr.err()
+}
+
+// ValidateLinks checks every Button.Link and Attachment.URL of the email against
+// the link policy configured with the options package.
+func (m *Mailer) ValidateLinks(email Email) error {
+	return ValidateLinks(email, m.linkPolicy)
+}
+
+// validateLinks records link policy violations of the email in verr
+func validateLinks(verr *ValidationError, email Email, policy LinkPolicy) {
+	for i, action := range email.Body.Actions {
+		if err := policy.Check(action.Button.Link); err != nil {
+			verr.add(fmt.Sprintf("Body.Actions[%d].Button.Link", i), "%v", err)
+		}
+	}
+	for i, attachment := range email.Body.Attachments {
+		if err := policy.Check(attachment.URL); err != nil {
+			verr.add(fmt.Sprintf("Body.Attachments[%d].URL", i), "%v", err)
+		}
+	}
+}
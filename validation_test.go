@@ -0,0 +1,238 @@
+package mailingo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestLinkPolicyCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy LinkPolicy
+		link   string
+		valid  bool
+	}{
+		{"HTTPS", LinkPolicy{}, "https://example.com/confirm", true},
+		{"Empty", LinkPolicy{}, "", true},
+		{"JavaScript", LinkPolicy{}, "javascript:alert(1)", false},
+		{"JavaScriptMixedCase", LinkPolicy{AllowInsecure: true, AllowRelative: true}, "JavaScript:alert(1)", false},
+		{"Data", LinkPolicy{}, "data:text/html;base64,PHNjcmlwdD4=", false},
+		{"HTTP", LinkPolicy{}, "http://example.com", false},
+		{"HTTPAllowed", LinkPolicy{AllowInsecure: true}, "http://example.com", true},
+		{"Relative", LinkPolicy{}, "/account", false},
+		{"RelativeAllowed", LinkPolicy{AllowRelative: true}, "/account", true},
+		{"ProtocolRelative", LinkPolicy{AllowRelative: true}, "//evil.example/x", false},
+		{"ProtocolRelativeBackslash", LinkPolicy{AllowRelative: true}, `/\evil.example/x`, false},
+		{"Backslash", LinkPolicy{AllowRelative: true}, `\evil.example/x`, false},
+		{"Mailto", LinkPolicy{}, "mailto:support@example.com", false},
+		{"MailtoAllowed", LinkPolicy{AllowMailto: true}, "mailto:support@example.com", true},
+		{"FTP", LinkPolicy{}, "ftp://example.com/file", false},
+		{"NoHost", LinkPolicy{}, "https:///path", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.link)
+			if (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v for %q, got error: %v", tt.valid, tt.link, err)
+			}
+		})
+	}
+}
+
+func TestValidateLinksFieldPaths(t *testing.T) {
+	email := Email{
+		Body: Body{
+			Actions: []Action{
+				{Button: Button{Text: "OK", Link: "https://example.com"}},
+				{Button: Button{Text: "Bad", Link: "javascript:alert(1)"}},
+			},
+			Attachments: []Attachment{
+				{Name: "doc.pdf", URL: "http://example.com/doc.pdf"},
+			},
+		},
+	}
+
+	err := ValidateLinks(email, LinkPolicy{})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+
+	if len(verr.Errors) != 2 {
+		t.Fatalf("Expected 2 field errors, got %d: %v", len(verr.Errors), err)
+	}
+
+	if verr.Errors[0].Field != "Body.Actions[1].Button.Link" {
+		t.Errorf("Unexpected field path %s", verr.Errors[0].Field)
+	}
+
+	if verr.Errors[1].Field != "Body.Attachments[0].URL" {
+		t.Errorf("Unexpected field path %s", verr.Errors[1].Field)
+	}
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Error("Field errors should be reachable with errors.As")
+	}
+}
+
+func TestGenerateHTMLWithLinkValidation(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+			Actions: []Action{
+				{Button: Button{Text: "Click", Link: "javascript:alert(1)"}},
+			},
+		},
+	}
+
+	// Without validation the link is silently neutralized
+	html, err := New(product, DefaultTheme).GenerateHTML(email, "en")
+	if err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	if !strings.Contains(html, "#ZgotmplZ") {
+		t.Error("Unvalidated unsafe link should be neutralized by html/template")
+	}
+
+	// With validation rendering fails with a field path
+	mailer := New(product, DefaultTheme, options.WithLinkValidation())
+	_, err = mailer.GenerateHTML(email, "en")
+	if err == nil {
+		t.Fatal("GenerateHTML should fail for unsafe links")
+	}
+
+	if !strings.Contains(err.Error(), "Body.Actions[0].Button.Link") {
+		t.Errorf("Error should contain field path, got: %v", err)
+	}
+
+	// Relaxed policy accepts plain HTTP links
+	email.Body.Actions[0].Button.Link = "http://example.com"
+	mailer = New(product, DefaultTheme, options.WithLinkValidation(), options.WithInsecureLinks())
+	if _, err := mailer.GenerateHTML(email, "en"); err != nil {
+		t.Errorf("GenerateHTML should accept HTTP links with WithInsecureLinks: %v", err)
+	}
+}
+
+func TestEmailValidate(t *testing.T) {
+	valid := Email{
+		Body: Body{
+			Name:       "Test User",
+			Dictionary: []Entry{{Key: "Username", Value: "test"}},
+			Table: Table{
+				Data: [][]Entry{
+					{{Key: "Item"}, {Key: "Price"}},
+					{{Value: "Widget"}, {Value: "$9.99"}},
+				},
+			},
+			Actions: []Action{
+				{Button: Button{Text: "Confirm", Link: "https://example.com/confirm"}},
+			},
+			Attachments: []Attachment{
+				{Name: "doc.pdf", URL: "https://example.com/doc.pdf"},
+			},
+		},
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Valid email should pass validation: %v", err)
+	}
+
+	invalid := Email{
+		Body: Body{
+			Table: Table{
+				Data: [][]Entry{
+					{{Key: "Item"}, {Key: "Price"}},
+					{{Value: "Widget"}, {Value: "$9.99"}, {Value: "extra"}},
+				},
+			},
+			Actions: []Action{
+				{Button: Button{Text: "Confirm"}},
+			},
+			Attachments: []Attachment{
+				{Name: "doc.pdf"},
+			},
+		},
+		SMTPAttachments: []SMTPAttachment{
+			{Filename: "report.pdf"},
+		},
+	}
+
+	err := invalid.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	expected := []string{
+		"Body.Table.Data[1]",
+		"Body.Actions[0].Button.Link",
+		"Body.Attachments[0].URL",
+		"SMTPAttachments[0].Content",
+	}
+
+	if len(verr.Errors) != len(expected) {
+		t.Fatalf("Expected %d field errors, got %d: %v", len(expected), len(verr.Errors), err)
+	}
+
+	for i, field := range expected {
+		if verr.Errors[i].Field != field {
+			t.Errorf("Expected field %s, got %s", field, verr.Errors[i].Field)
+		}
+	}
+}
+
+func TestGenerateWithValidation(t *testing.T) {
+	product := Product{
+		Name: "Test Product",
+		Link: "https://example.com",
+	}
+
+	mailer := New(product, DefaultTheme, options.WithValidation())
+
+	email := Email{
+		Body: Body{
+			Name: "Test User",
+			Actions: []Action{
+				{Button: Button{Text: "Confirm", Link: "http://example.com/confirm"}},
+			},
+			Attachments: []Attachment{
+				{Name: "doc.pdf"},
+			},
+		},
+	}
+
+	_, err := mailer.GenerateHTML(email, "en")
+	if err == nil {
+		t.Fatal("GenerateHTML should fail for invalid email")
+	}
+
+	for _, field := range []string{"Body.Actions[0].Button.Link", "Body.Attachments[0].URL"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Error should mention %s, got: %v", field, err)
+		}
+	}
+
+	if _, err := mailer.GeneratePlainText(email, "en"); err == nil {
+		t.Error("GeneratePlainText should fail for invalid email")
+	}
+
+	// Validation is opt-in
+	if _, err := New(product, DefaultTheme).GenerateHTML(email, "en"); err != nil {
+		t.Errorf("GenerateHTML should not validate by default: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package mailingo
+
+// Recipient attributes selecting message variants with Body.Formality and Body.Gender.
+// Other values can be used as well, as long as the message files use the same suffixes.
+const (
+	FormalityFormal   = "formal"   // Formal address, e.g. "usted", "vous" or "Sie"
+	FormalityInformal = "informal" // Informal address, e.g. "tú", "tu" or "du"
+
+	GenderFeminine  = "feminine"
+	GenderMasculine = "masculine"
+)
+
+// variantSuffixes returns the message ID suffixes of the variants selected by the
+// recipient attributes, most specific first: ".formal.feminine", ".formal", ".feminine"
+func variantSuffixes(formality, gender string) []string {
+	var suffixes []string
+	if formality != "" && gender != "" {
+		suffixes = append(suffixes, "."+formality+"."+gender)
+	}
+	if formality != "" {
+		suffixes = append(suffixes, "."+formality)
+	}
+	if gender != "" {
+		suffixes = append(suffixes, "."+gender)
+	}
+	return suffixes
+}
+
+// withVariants returns a localizer that looks up the variants selected by the
+// body's recipient attributes before the neutral messages
+func (l *localizer) withVariants(body Body) *localizer {
+	suffixes := variantSuffixes(body.Formality, body.Gender)
+	if len(suffixes) == 0 {
+		return l
+	}
+	variant := *l
+	variant.variants = suffixes
+	return &variant
+}
+
+// variantIDs returns the message IDs to look up for a message, the variants first
+func (l *localizer) variantIDs(messageID string) []string {
+	if len(l.variants) == 0 {
+		return []string{messageID}
+	}
+	ids := make([]string, 0, len(l.variants)+1)
+	for _, suffix := range l.variants {
+		ids = append(ids, messageID+suffix)
+	}
+	return append(ids, messageID)
+}
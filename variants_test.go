@@ -0,0 +1,156 @@
+package mailingo
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lib-x/mailingo/options"
+)
+
+func TestVariants(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+	err := mailer.AddMessages("es", map[string]string{
+		"greeting":                 "Hola",
+		"greeting.formal":          "Estimado",
+		"greeting.formal.feminine": "Estimada",
+		"email.welcome":            "Bienvenido",
+		"email.welcome.feminine":   "Bienvenida",
+		"email.intro":              "Gracias por registrarte",
+		"email.intro.formal":       "Gracias por registrarse",
+	})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	tests := []struct {
+		formality, gender string
+		want              []string
+	}{
+		{"", "", []string{"Hola", "Bienvenido", "Gracias por registrarte"}},
+		{FormalityFormal, "", []string{"Estimado", "Bienvenido", "Gracias por registrarse"}},
+		{FormalityFormal, GenderFeminine, []string{"Estimada", "Bienvenida", "Gracias por registrarse"}},
+		{FormalityInformal, GenderFeminine, []string{"Hola", "Bienvenida", "Gracias por registrarte"}},
+		{"", GenderMasculine, []string{"Hola", "Bienvenido", "Gracias por registrarte"}},
+	}
+	for _, tt := range tests {
+		email := Email{Body: Body{
+			Name:      "Ana",
+			Title:     "email.welcome",
+			Intros:    []string{"email.intro"},
+			Formality: tt.formality,
+			Gender:    tt.gender,
+		}}
+		text, err := mailer.GeneratePlainText(email, "es")
+		if err != nil {
+			t.Fatalf("GeneratePlainText failed: %v", err)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(text, s) {
+				t.Errorf("%s/%s: text should contain %q, got:\n%s", tt.formality, tt.gender, s, text)
+			}
+		}
+	}
+}
+
+func TestVariantsPreferNeutralOverFallback(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme, options.WithFallback("es-AR", "es"))
+	err := mailer.AddMessages("en", map[string]string{"email.thanks": "Thank you", "email.thanks.formal": "We thank you"})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+	err = mailer.AddMessages("es", map[string]string{"greeting": "Hola", "greeting.formal": "Estimado", "email.thanks": "Gracias"})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	email := Email{Body: Body{Intros: []string{"email.thanks"}, Formality: FormalityFormal}}
+
+	text, err := mailer.GeneratePlainText(email, "es-AR")
+	if err != nil {
+		t.Fatalf("GeneratePlainText failed: %v", err)
+	}
+	if !strings.Contains(text, "Gracias") || strings.Contains(text, "We thank you") {
+		t.Errorf("Neutral Spanish message should win over an English variant, got:\n%s", text)
+	}
+
+	traces := mailer.TraceEmail(Email{Body: Body{Greeting: "greeting", Formality: FormalityFormal}}, "es-AR")
+	if slices.ContainsFunc(traces, func(trace MessageTrace) bool { return trace.MessageID == "greeting.formal" }) {
+		t.Errorf("Variants should be reported by the trace of their message, got %v", traces)
+	}
+	i := slices.IndexFunc(traces, func(trace MessageTrace) bool { return trace.MessageID == "greeting" })
+	if i < 0 || traces[i].String() != `"greeting" es-AR: es variant "greeting.formal" (chain es-AR, es, en)` {
+		t.Errorf("Unexpected traces %v", traces)
+	}
+}
+
+func TestVariantsBatch(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme)
+	err := mailer.AddMessages("es", map[string]string{
+		"greeting":               "Hola",
+		"greeting.formal":        "Estimado",
+		"email.welcome":          "Bienvenido",
+		"email.welcome.feminine": "Bienvenida",
+	})
+	if err != nil {
+		t.Fatalf("AddMessages failed: %v", err)
+	}
+
+	base := Email{Body: Body{Title: "email.welcome"}}
+	recipients := []Recipient{
+		{Name: "Ana", Lang: "es", Gender: GenderFeminine},
+		{Name: "Luis", Lang: "es", Formality: FormalityFormal},
+	}
+	results := mailer.RenderBatch(context.Background(), base, slices.Values(recipients), BatchOptions{SkipHTML: true})
+
+	want := map[string]string{"Ana": "Bienvenida", "Luis": "Estimado Luis"}
+	for result := range results {
+		if result.Err != nil {
+			t.Fatalf("Render failed: %v", result.Err)
+		}
+		if s := want[result.Recipient.Name]; !strings.Contains(result.Text, s) {
+			t.Errorf("%s: text should contain %q, got:\n%s", result.Recipient.Name, s, result.Text)
+		}
+	}
+}
+
+func TestVariantsTemplateFuncs(t *testing.T) {
+	mailer := New(Product{Name: "Acme"}, DefaultTheme,
+		options.WithCustomTemplateString(`{{t "greeting"}} {{.Body.Name}}|{{tn "cart.items" 2}}`))
+	err := mailer.LoadMessageFileFS(fstest.MapFS{
+		"de.json": {Data: []byte(`{
+			"greeting": "Hallo",
+			"greeting.formal": "Guten Tag",
+			"cart.items": {"one": "Du hast {{.Count}} Artikel", "other": "Du hast {{.Count}} Artikel"},
+			"cart.items.formal": {"one": "Sie haben {{.Count}} Artikel", "other": "Sie haben {{.Count}} Artikel"}
+		}`)},
+	}, "de.json")
+	if err != nil {
+		t.Fatalf("LoadMessageFileFS failed: %v", err)
+	}
+
+	tests := []struct {
+		formality, want string
+	}{
+		{"", "Hallo Jana|Du hast 2 Artikel"},
+		{FormalityFormal, "Guten Tag Jana|Sie haben 2 Artikel"},
+	}
+	for _, tt := range tests {
+		html, err := mailer.GenerateHTML(Email{Body: Body{Name: "Jana", Formality: tt.formality}}, "de")
+		if err != nil {
+			t.Fatalf("GenerateHTML failed: %v", err)
+		}
+		if html != tt.want {
+			t.Errorf("Formality %q: expected %q, got %q", tt.formality, tt.want, html)
+		}
+	}
+}
+
+func TestVariantsValidation(t *testing.T) {
+	err := Email{Body: Body{Formality: "very formal", Gender: GenderFeminine}}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "Body.Formality") {
+		t.Errorf("Formality with spaces should be invalid, got %v", err)
+	}
+}